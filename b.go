@@ -0,0 +1,125 @@
+package testo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/metafates/testo/plugin"
+)
+
+// CommonB is the interface common for all [B] derivatives.
+type CommonB interface {
+	testing.TB
+
+	unwrap() *B
+}
+
+type (
+	// B is a wrapper for [testing.B].
+	// It is used as a [testing.B] replacement in benchmark suites, see [BenchSuite].
+	//
+	// Similar to [T], it is embedded into new struct type along with plugins:
+	//
+	//	type MyB struct {
+	//		*B
+	//
+	//		SomePlugin
+	//	}
+	//
+	// Plugins may embed *B to share the same value as the running benchmark's B.
+	// Plugin overrides are not applied to B.
+	B struct {
+		*testing.B
+
+		parent *B
+		plugin plugin.Spec
+
+		// levelOptions stores option passes for
+		// current level through [BenchSuite].
+		levelOptions []plugin.Option
+
+		suiteName string
+
+		// info information required for [InspectB].
+		info plugin.TInfo
+	}
+
+	actualB = B
+)
+
+// InspectB returns meta information about given b.
+//
+// It is the same as [Inspect] but for benchmarks.
+// Only Plugins and Test fields are set.
+func InspectB[B CommonB](b B) plugin.TInfo {
+	return b.unwrap().info
+}
+
+// SuiteName returns current suite name.
+func (b *B) SuiteName() string {
+	for b != nil {
+		if b.suiteName != "" {
+			return b.suiteName
+		}
+
+		b = b.parent
+	}
+
+	return ""
+}
+
+func (b *B) unwrap() *B {
+	return b
+}
+
+func (b *B) options() []plugin.Option {
+	options := b.levelOptions
+
+	for parent := b.parent; parent != nil; parent = parent.parent {
+		for _, o := range parent.levelOptions {
+			if o.Propagate {
+				options = append(options, o)
+			}
+		}
+	}
+
+	return options
+}
+
+// constructB will construct a new user B (inherits actual B)
+// with the given parent and options.
+func constructB[B CommonB](
+	b *testing.B,
+	parent *B,
+	fill func(b *actualB),
+	options ...plugin.Option,
+) B {
+	b.Helper()
+
+	seedB := actualB{
+		B:            b,
+		levelOptions: options,
+		plugin:       plugin.MergeSpecs(),
+	}
+
+	if parent != nil {
+		seedB.parent = (*parent).unwrap()
+	}
+
+	if fill != nil {
+		fill(&seedB)
+	}
+
+	// special case: B is *testo.B
+	if reflect.TypeFor[B]() == reflect.TypeFor[*actualB]() {
+		//nolint:forcetypeassert // checked with reflection
+		return any(&seedB).(B)
+	}
+
+	value, plugins := newValue(&seedB, parent)
+
+	seedB.info.Plugins = plugins
+	seedB.plugin = mergePlugins(plugins...)
+
+	return value
+}
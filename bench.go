@@ -0,0 +1,204 @@
+package testo
+
+import (
+	"reflect"
+	"runtime/debug"
+	"slices"
+	"testing"
+
+	"github.com/metafates/testo/internal/reflectutil"
+	"github.com/metafates/testo/plugin"
+)
+
+// BenchSuite will run the benchmarks under the given suite.
+//
+// Suite type must be a pointer in a form of *MySuite.
+//
+// Benchmarks are suite methods in a form of:
+//
+//	func (Suite) BenchmarkFoo(b B, [params struct{...}])
+//
+// Params are provided by CasesXXX methods, as for parametrized tests in [RunSuite].
+//
+// BeforeAll and AfterAll hooks are run once.
+// BeforeEach and AfterEach hooks are run around each call of the benchmark,
+// which happens several times with different b.N.
+// Time spent in them is not measured.
+//
+// Hooks must accept the same B type as benchmarks,
+// therefore benchmark suites can not be run with [RunSuite] and vice versa.
+func BenchSuite[Suite any, B CommonB](b *testing.B, options ...plugin.Option) {
+	b.Helper()
+
+	checkSuiteType[Suite]()
+
+	suiteName := reflectutil.NameOf[Suite]()
+
+	options = append(getDefaultOptions(), options...)
+
+	b.Run(suiteName, func(rawB *testing.B) {
+		b := constructB[B](
+			rawB,
+			nil,
+			func(b *actualB) {
+				b.suiteName = suiteName
+			},
+			options...,
+		)
+
+		runBenchSuite[Suite](b)
+	})
+}
+
+func runBenchSuite[Suite any, B CommonB](b B) {
+	b.Helper()
+
+	suiteHooks := suiteHooksOf[Suite](b)
+
+	suite := reflectutil.Make[Suite]()
+
+	cases := suiteCasesOf[Suite](b)
+	benchmarks := benchmarksFor[Suite](b, cases)
+
+	benchmarks.Plans = []plugin.Plan{
+		tagsPlanOf(b),
+		shardPlanOf(b, b.unwrap().SuiteName(), nil),
+	}
+
+	b.unwrap().plugin.Hooks.BeforeAll.Run()
+	suiteHooks.BeforeAll(suite, b)
+
+	defer func() {
+		suiteHooks.AfterAll(suite, b)
+		b.unwrap().plugin.Hooks.AfterAll.Run()
+	}()
+
+	parent := b
+
+	planned := applyPlan(
+		benchmarks.Get(cloneSuite(suite)),
+		append(slices.Clone(benchmarks.Plans), b.unwrap().plugin.Plan)...,
+	)
+
+	for _, benchmark := range planned {
+		b.unwrap().B.Run(benchmark.Name, func(rawB *testing.B) {
+			b := constructB(
+				rawB,
+				&parent,
+				func(b *actualB) {
+					b.info.Test = benchmark.Info
+				},
+			)
+
+			runBenchmark(b, cloneSuite(suite), suiteHooks, benchmark)
+		})
+	}
+}
+
+func runBenchmark[Suite any, B CommonB](
+	b B,
+	s Suite,
+	hooks suiteHooks[Suite, B],
+	benchmark suiteTest[Suite, B],
+) {
+	// registered first, so that panics of the hooks are recovered too
+	defer func() {
+		if r := recover(); r != nil {
+			b.unwrap().info.Panic = &plugin.PanicInfo{
+				Value: r,
+				Trace: string(debug.Stack()),
+			}
+
+			b.Fatalf("benchmark %q panicked: %v", b.Name(), r)
+		}
+	}()
+
+	b.unwrap().plugin.Hooks.BeforeEach.Run()
+
+	if benchmark.Skip != "" {
+		defer b.unwrap().plugin.Hooks.AfterEach.Run()
+
+		b.Skip(benchmark.Skip)
+	}
+
+	hooks.BeforeEach(s, b)
+
+	defer func() {
+		b.unwrap().StopTimer()
+
+		hooks.AfterEach(s, b)
+		b.unwrap().plugin.Hooks.AfterEach.Run()
+	}()
+
+	b.unwrap().ResetTimer()
+
+	benchmark.Run(s, b)
+}
+
+func benchmarksFor[Suite any, B CommonB](
+	b B,
+	cases map[string]suiteCase[Suite],
+) suiteTests[Suite, B] {
+	vt := reflect.TypeFor[Suite]()
+
+	var benchmarks suiteTests[Suite, B]
+
+	benchmarks.Tags = suiteTagsOf[Suite](b)
+
+	for i := range vt.NumMethod() {
+		method := vt.Method(i)
+
+		if !isTest(method.Name, "Benchmark") {
+			continue
+		}
+
+		isValidOut := method.Type.NumOut() == 0
+		isValidIn := method.Type.NumIn() >= 2 && method.Type.In(1) == reflect.TypeFor[B]() &&
+			(method.Type.NumIn() == 2 ||
+				method.Type.NumIn() == 3 && method.Type.In(2).Kind() == reflect.Struct)
+
+		if !isValidIn || !isValidOut {
+			b.Fatalf(
+				"wrong signature for %[1]s.%[2]s, must be: func %[1]s.%[2]s(%[3]s, [struct{...}])",
+				vt,
+				method.Name,
+				reflect.TypeFor[B](),
+			)
+
+			continue
+		}
+
+		if method.Type.NumIn() == 2 {
+			benchmarks.Regular = append(benchmarks.Regular, suiteTest[Suite, B]{
+				Name: method.Name,
+				Info: plugin.RegularTestInfo{
+					RawBaseName: method.Name,
+					Level:       1,
+				},
+				Run: func(s Suite, b B) {
+					method.Func.Call([]reflect.Value{reflect.ValueOf(s), reflect.ValueOf(b)})
+				},
+			})
+
+			continue
+		}
+
+		param := method.Type.In(2)
+
+		benchmarks.Parametrized = append(
+			benchmarks.Parametrized,
+			newParametrizedTest(
+				method.Name,
+				param,
+				requiredCasesOf(b, method.Name, param, cases),
+				Cartesian(),
+				0,
+				func(s Suite, b B, params reflect.Value) {
+					method.Func.Call([]reflect.Value{reflect.ValueOf(s), reflect.ValueOf(b), params})
+				},
+			),
+		)
+	}
+
+	return benchmarks
+}
@@ -0,0 +1,55 @@
+package testo
+
+// Case is a named parametrized test case.
+//
+// CasesXXX suite methods may return a slice of cases instead of bare values
+// to give cases meaningful names:
+//
+//	func (Suite) CasesInput() []testo.Case[string] {
+//		return []testo.Case[string]{
+//			{Name: "empty input", Value: ""},
+//			{Name: "unicode", Value: "привет", Tags: []string{"i18n"}},
+//			{Name: "huge", Value: huge, Skip: "too slow, see #42"},
+//		}
+//	}
+//
+// Then the tests for these cases are named like "TestFoo/empty_input"
+// instead of "TestFoo_case_1".
+//
+// Returning map[string]V is a shorthand for cases with names only.
+type Case[V any] struct {
+	// Name of the case.
+	// It is used in the test name, therefore should be unique among other cases.
+	Name string
+
+	// Value of the case passed to the test.
+	Value V
+
+	// Skip is the reason to skip tests with this case.
+	// Empty string means the case is not skipped.
+	Skip string
+
+	// Tags of the case.
+	// They are available to plugins with [plugin.ParametrizedTestInfo].
+	Tags []string
+}
+
+func (c Case[V]) caseInfo() caseInfo {
+	return caseInfo{
+		Name: c.Name,
+		Skip: c.Skip,
+		Tags: c.Tags,
+	}
+}
+
+// caseInfo is the information about [Case] except its value.
+type caseInfo struct {
+	Name string
+	Skip string
+	Tags []string
+}
+
+// anyCase is implemented by [Case] of any type.
+type anyCase interface {
+	caseInfo() caseInfo
+}
@@ -0,0 +1,398 @@
+package testo
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/internal/reflectutil"
+)
+
+// caseFilesDir is the directory case files are loaded from,
+// relative to the package being tested.
+const caseFilesDir = "testdata"
+
+// caseFile is a file with parametrized cases, one per row.
+type caseFile struct {
+	// Path of the file relative to the package being tested.
+	Path string
+
+	Rows []CaseFileRow
+}
+
+// CaseFileRow is a single row of the case file.
+type CaseFileRow struct {
+	// Key of the row: object key for JSON objects,
+	// or row number starting from 1 otherwise.
+	Key string
+
+	// Line where the row starts.
+	Line int
+
+	// Decode the row into the value pointed by v.
+	Decode func(v any) error
+}
+
+// CaseFileFormat returns rows of the case file with the given contents.
+type CaseFileFormat func(data []byte) ([]CaseFileRow, error)
+
+//nolint:gochecknoglobals // global variables are required in this case.
+var (
+	caseFileFormats = map[string]CaseFileFormat{
+		".json": jsonCaseRows,
+		".csv":  csvCaseRows,
+	}
+	caseFileFormatsMutex sync.RWMutex
+)
+
+// RegisterCaseFileFormat registers the format of case files with the given extensions,
+// e.g. ".yaml", replacing the format previously registered for them.
+//
+// JSON and CSV formats are registered by default.
+func RegisterCaseFileFormat(format CaseFileFormat, exts ...string) {
+	caseFileFormatsMutex.Lock()
+	defer caseFileFormatsMutex.Unlock()
+
+	for _, ext := range exts {
+		caseFileFormats[ext] = format
+	}
+}
+
+func caseFileFormatOf(ext string) (CaseFileFormat, error) {
+	caseFileFormatsMutex.RLock()
+	defer caseFileFormatsMutex.RUnlock()
+
+	if format, ok := caseFileFormats[ext]; ok {
+		return format, nil
+	}
+
+	exts := maputil.Keys(caseFileFormats)
+	slices.Sort(exts)
+
+	return nil, fmt.Errorf("unsupported format %q, must be one of %s", ext, strings.Join(exts, ", "))
+}
+
+// suiteCaseFilesOf returns case files declared by the "CaseFiles" suite method.
+//
+//	func (Suite) CaseFiles() map[string]string {
+//		return map[string]string{"User": "users.json"}
+//	}
+//
+// Files are loaded from testdata directory.
+func suiteCaseFilesOf[Suite any, T fataller](t T) map[string]*caseFile {
+	const name = "CaseFiles"
+
+	suite := reflect.TypeFor[Suite]()
+
+	method, ok := suite.MethodByName(name)
+	if !ok {
+		return nil
+	}
+
+	f, ok := method.Func.Interface().(func(Suite) map[string]string)
+	if !ok {
+		t.Fatalf(
+			"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s() map[string]string",
+			suite, name,
+		)
+
+		return nil
+	}
+
+	paths := f(reflectutil.Make[Suite]())
+
+	params := maputil.Keys(paths)
+	slices.Sort(params)
+
+	files := make(map[string]*caseFile, len(paths))
+
+	for _, param := range params {
+		file, err := loadCaseFile(filepath.Join(caseFilesDir, paths[param]))
+		if err != nil {
+			t.Fatalf("wrong case files for %[1]s: param %[2]q: %[3]v", suite, param, err)
+
+			return nil
+		}
+
+		files[param] = file
+	}
+
+	return files
+}
+
+// loadCaseFile loads rows of the case file at the given path.
+// Format is chosen by the file extension.
+func loadCaseFile(path string) (*caseFile, error) {
+	format, err := caseFileFormatOf(filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := format(data)
+
+	var lineErr lineError
+
+	if errors.As(err, &lineErr) {
+		return nil, fmt.Errorf("%s:%d: %w", path, lineErr.Line, lineErr.Err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &caseFile{Path: path, Rows: rows}, nil
+}
+
+// values returns the case values decoded from the file rows into the given type.
+//
+// Values are named after the file name without extension and the row key,
+// e.g. "users_alice", so that rows of different files do not share names.
+func (f *caseFile) values(typ reflect.Type) ([]suiteCaseValue, error) {
+	values := make([]suiteCaseValue, 0, len(f.Rows))
+
+	stem := strings.TrimSuffix(filepath.Base(f.Path), filepath.Ext(f.Path))
+
+	for i, row := range f.Rows {
+		value := reflect.New(typ)
+
+		if err := row.Decode(value.Interface()); err != nil {
+			return nil, fmt.Errorf("%s:%d: row %s: %w", f.Path, row.Line, row.Key, err)
+		}
+
+		values = append(values, suiteCaseValue{
+			caseInfo: caseInfo{Name: stem + "_" + row.Key},
+			Value:    value.Elem(),
+			Index:    i + 1,
+		})
+	}
+
+	return values, nil
+}
+
+// lineAt returns the line of the first value at or after the given offset.
+func lineAt(data []byte, offset int64) int {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) != -1 {
+		offset++
+	}
+
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// lineError is an error at the given line of the file.
+type lineError struct {
+	Line int
+	Err  error
+}
+
+func (e lineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e lineError) Unwrap() error {
+	return e.Err
+}
+
+func jsonCaseRows(data []byte) ([]CaseFileRow, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, lineError{Line: lineAt(data, dec.InputOffset()), Err: err}
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok || (delim != '[' && delim != '{') {
+		return nil, lineError{Line: 1, Err: errors.New("must be an array or an object")}
+	}
+
+	var rows []CaseFileRow
+
+	for i := 1; dec.More(); i++ {
+		key := strconv.Itoa(i)
+
+		if delim == '{' {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, lineError{Line: lineAt(data, dec.InputOffset()), Err: err}
+			}
+
+			//nolint:forcetypeassert // object keys are always strings
+			key = tok.(string)
+		}
+
+		line := lineAt(data, dec.InputOffset())
+
+		var raw json.RawMessage
+
+		if err := dec.Decode(&raw); err != nil {
+			return nil, lineError{Line: line, Err: err}
+		}
+
+		rows = append(rows, CaseFileRow{
+			Key:  key,
+			Line: line,
+			Decode: func(v any) error {
+				return json.Unmarshal(raw, v)
+			},
+		})
+	}
+
+	return rows, nil
+}
+
+// csvCaseRows returns rows of the CSV file with a header.
+//
+// Columns are decoded into the struct fields with the same name, ignoring case,
+// or with the matching "csv" tag.
+// Values which are not structs are decoded from the single column.
+func csvCaseRows(data []byte) ([]CaseFileRow, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+
+	if err != nil {
+		// csv errors already include the line
+		return nil, err
+	}
+
+	var rows []CaseFileRow
+
+	for i := 1; ; i++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+
+		rows = append(rows, CaseFileRow{
+			Key:  strconv.Itoa(i),
+			Line: line,
+			Decode: func(v any) error {
+				return decodeCSVRecord(header, record, reflect.ValueOf(v).Elem())
+			},
+		})
+	}
+
+	return rows, nil
+}
+
+func decodeCSVRecord(header, record []string, v reflect.Value) error {
+	if v.Kind() != reflect.Struct {
+		if len(record) != 1 {
+			return fmt.Errorf("can not decode %d columns into %s, must be 1", len(record), v.Type())
+		}
+
+		return decodeCSVValue(record[0], v)
+	}
+
+	for i, column := range header {
+		field, ok := csvFieldOf(v, column)
+		if !ok {
+			return fmt.Errorf("column %q does not match any field of %s", column, v.Type())
+		}
+
+		if err := decodeCSVValue(record[i], field); err != nil {
+			return fmt.Errorf("column %q: %w", column, err)
+		}
+	}
+
+	return nil
+}
+
+// csvFieldOf returns the field of the struct value for the given column.
+func csvFieldOf(v reflect.Value, column string) (reflect.Value, bool) {
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if tag, ok := field.Tag.Lookup("csv"); ok {
+			if tag == column {
+				return v.Field(i), true
+			}
+
+			continue
+		}
+
+		if strings.EqualFold(field.Name, column) {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+func decodeCSVValue(s string, v reflect.Value) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(n)
+
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+package testo
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/internal/reflectutil"
+	"github.com/metafates/testo/plugin"
+)
+
+// suiteDependenciesOf returns test dependencies declared by the "Dependencies" suite method.
+//
+//	func (Suite) Dependencies() map[string][]string {
+//		return map[string][]string{
+//			"TestUpdate": {"TestCreate"},
+//			"TestDelete": {"TestUpdate"},
+//		}
+//	}
+func suiteDependenciesOf[Suite any, T fataller](t T) map[string][]string {
+	const name = "Dependencies"
+
+	suite := reflect.TypeFor[Suite]()
+
+	method, ok := suite.MethodByName(name)
+	if !ok {
+		return nil
+	}
+
+	f, ok := method.Func.Interface().(func(Suite) map[string][]string)
+	if !ok {
+		t.Fatalf(
+			"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s() map[string][]string",
+			suite, name,
+		)
+
+		return nil
+	}
+
+	dependencies := f(reflectutil.Make[Suite]())
+
+	isKnown := func(name string) bool {
+		m, ok := suite.MethodByName(name)
+
+		return ok && isTestMethod(m.Name)
+	}
+
+	var unknown []string
+
+	for test, prerequisites := range dependencies {
+		if !isKnown(test) {
+			unknown = append(unknown, test)
+		}
+
+		for _, p := range prerequisites {
+			if !isKnown(p) && !slices.Contains(unknown, p) {
+				unknown = append(unknown, p)
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+
+		t.Fatalf(
+			"wrong dependencies for %[1]s: tests %[2]s not found",
+			suite, strings.Join(unknown, ", "),
+		)
+
+		return nil
+	}
+
+	if cycle := dependencyCycle(dependencies); cycle != nil {
+		t.Fatalf(
+			"wrong dependencies for %[1]s: tests form a cycle: %[2]s",
+			suite, strings.Join(cycle, " -> "),
+		)
+
+		return nil
+	}
+
+	return dependencies
+}
+
+// dependencyCycle returns a cycle of the given dependencies, if any.
+func dependencyCycle(dependencies map[string][]string) []string {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := make(map[string]int, len(dependencies))
+
+	var visit func(test string, path []string) []string
+
+	visit = func(test string, path []string) []string {
+		path = append(path, test)
+
+		switch state[test] {
+		case visited:
+			return nil
+
+		case visiting:
+			return path
+		}
+
+		state[test] = visiting
+
+		for _, p := range dependencies[test] {
+			if cycle := visit(p, path); cycle != nil {
+				return cycle
+			}
+		}
+
+		state[test] = visited
+
+		return nil
+	}
+
+	tests := maputil.Keys(dependencies)
+	slices.Sort(tests)
+
+	for _, test := range tests {
+		if cycle := visit(test, nil); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// dependencyOrder sorts tests so that they follow the tests they depend on.
+//
+// Tests which others depend on are marked as sequential,
+// so that they have finished by the time their dependents are run.
+// Otherwise parallel tests would run only after all other tests are started.
+//
+// Order of independent tests is preserved.
+func dependencyOrder[Suite any, T any](
+	tests []suiteTest[Suite, T],
+	dependencies map[string][]string,
+) []suiteTest[Suite, T] {
+	levelOf := make(map[string]int)
+
+	var level func(test string) int
+
+	level = func(test string) int {
+		if l, ok := levelOf[test]; ok {
+			return l
+		}
+
+		var l int
+
+		for _, p := range dependencies[test] {
+			l = max(l, level(p)+1)
+		}
+
+		levelOf[test] = l
+
+		return l
+	}
+
+	isPrerequisite := make(map[string]bool)
+
+	for _, prerequisites := range dependencies {
+		for _, p := range prerequisites {
+			isPrerequisite[p] = true
+		}
+	}
+
+	ordered := slices.Clone(tests)
+
+	for i, test := range ordered {
+		ordered[i].Sequential = isPrerequisite[rawBaseNameOf(test.Info)]
+	}
+
+	slices.SortStableFunc(ordered, func(a, b suiteTest[Suite, T]) int {
+		return cmp.Compare(level(rawBaseNameOf(a.Info)), level(rawBaseNameOf(b.Info)))
+	})
+
+	return ordered
+}
+
+// rawBaseNameOf returns the raw base name of the test with the given info.
+func rawBaseNameOf(info plugin.TestInfo) string {
+	switch info := info.(type) {
+	case plugin.RegularTestInfo:
+		return info.RawBaseName
+
+	case plugin.ParametrizedTestInfo:
+		return info.RawBaseName
+
+	default:
+		return ""
+	}
+}
+
+// testOutcome is the outcome of all runs of the test method.
+type testOutcome struct {
+	Runs, Failed, Skipped int
+}
+
+// testOutcomes records outcomes of the suite tests
+// by their raw base names.
+type testOutcomes struct {
+	mu       sync.Mutex
+	outcomes map[string]testOutcome
+
+	// planned tests, which may still be filtered out by -run flag.
+	planned map[string]struct{}
+}
+
+func newTestOutcomes() *testOutcomes {
+	return &testOutcomes{
+		outcomes: make(map[string]testOutcome),
+		planned:  make(map[string]struct{}),
+	}
+}
+
+// Plan records that the test is planned to run.
+func (o *testOutcomes) Plan(name string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.planned[name] = struct{}{}
+}
+
+// Record the outcome of the finished test.
+func (o *testOutcomes) Record(name string, t *testing.T) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	outcome := o.outcomes[name]
+
+	outcome.Runs++
+
+	switch {
+	case t.Failed():
+		outcome.Failed++
+
+	case t.Skipped():
+		outcome.Skipped++
+	}
+
+	o.outcomes[name] = outcome
+}
+
+// FailedTests returns sorted names of the tests which have failed.
+func (o *testOutcomes) FailedTests() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var failed []string
+
+	for name, outcome := range o.outcomes {
+		if outcome.Failed > 0 {
+			failed = append(failed, name)
+		}
+	}
+
+	slices.Sort(failed)
+
+	return failed
+}
+
+// Blocked returns the reason why a test with the given prerequisites can not run.
+// It returns empty string if all prerequisites have passed.
+func (o *testOutcomes) Blocked(prerequisites []string) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, p := range prerequisites {
+		outcome, ok := o.outcomes[p]
+		_, isPlanned := o.planned[p]
+
+		switch {
+		case !ok && isPlanned:
+			return fmt.Sprintf("prerequisite %s did not run, it is filtered out by -run or -skip flag", p)
+
+		case !ok:
+			return fmt.Sprintf("prerequisite %s did not run, it is filtered out by the plan, e.g. -testo.tags flag", p)
+
+		case outcome.Failed > 0:
+			return fmt.Sprintf("prerequisite %s failed", p)
+
+		case outcome.Skipped == outcome.Runs:
+			return fmt.Sprintf("prerequisite %s was skipped", p)
+		}
+	}
+
+	return ""
+}
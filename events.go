@@ -0,0 +1,168 @@
+package testo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/metafates/testo/plugin"
+)
+
+// eventStream delivers events of the suite run to the observer in order.
+//
+// Events are queued and delivered by the goroutine which found the queue idle,
+// so that the observer is never called concurrently
+// and events reported by the observer itself do not deadlock.
+type eventStream struct {
+	observer plugin.Observer
+
+	mu         sync.Mutex
+	queue      []plugin.Event
+	isDraining bool
+	lastID     int
+}
+
+// newEventStream returns a new stream for the given observer.
+// It returns nil if observer is nil.
+func newEventStream(observer plugin.Observer) *eventStream {
+	if observer == nil {
+		return nil
+	}
+
+	return &eventStream{observer: observer}
+}
+
+// nextID returns a new test ID.
+func (s *eventStream) nextID() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+
+	return s.lastID
+}
+
+func (s *eventStream) emit(event plugin.Event) {
+	s.mu.Lock()
+
+	s.queue = append(s.queue, event)
+
+	if s.isDraining {
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.isDraining = true
+
+	s.mu.Unlock()
+
+	s.drain()
+}
+
+// drain delivers queued events until the queue is empty.
+func (s *eventStream) drain() {
+	isDone := false
+
+	// Observer may exit the goroutine, e.g. by calling t.FailNow.
+	// The rest of the queue is then delivered by another goroutine,
+	// which is waited for, so that no event is left behind.
+	defer func() {
+		if isDone {
+			return
+		}
+
+		delivered := make(chan struct{})
+
+		go func() {
+			defer close(delivered)
+
+			s.drain()
+		}()
+
+		<-delivered
+	}()
+
+	for {
+		s.mu.Lock()
+
+		if len(s.queue) == 0 {
+			s.isDraining = false
+			isDone = true
+
+			s.mu.Unlock()
+
+			return
+		}
+
+		event := s.queue[0]
+		s.queue = s.queue[1:]
+
+		s.mu.Unlock()
+
+		s.observer(event)
+	}
+}
+
+// emit reports the event created by newEvent, if events of this T are observed.
+func (t *T) emit(newEvent func(meta plugin.EventMeta) plugin.Event) {
+	if t.events == nil || t.probing {
+		return
+	}
+
+	t.events.emit(newEvent(plugin.EventMeta{
+		Time: time.Now(),
+		Test: t.testID(),
+	}))
+}
+
+func (t *T) testID() plugin.TestID {
+	id := plugin.TestID{
+		ID:      t.id,
+		Name:    t.name(),
+		Info:    t.info.Test,
+		Attempt: t.info.Attempt,
+	}
+
+	if t.parent != nil {
+		id.ParentID = t.parent.id
+	}
+
+	return id
+}
+
+// addLog reports the message logged by the test.
+func (t *T) addLog(msg string) {
+	if t.isAbandoned() || t.probing {
+		return
+	}
+
+	t.emit(func(meta plugin.EventMeta) plugin.Event {
+		return plugin.Log{EventMeta: meta, Message: msg}
+	})
+}
+
+// start reports that the test has started.
+func (t *T) start() {
+	t.emit(func(meta plugin.EventMeta) plugin.Event {
+		return plugin.TestStarted{EventMeta: meta}
+	})
+}
+
+// observeCleanup returns f which reports the cleanup before running.
+func (t *T) observeCleanup(f func()) func() {
+	if t.events == nil {
+		return f
+	}
+
+	return func() {
+		t.emit(func(meta plugin.EventMeta) plugin.Event {
+			return plugin.Cleanup{EventMeta: meta}
+		})
+
+		f()
+	}
+}
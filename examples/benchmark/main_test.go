@@ -0,0 +1,46 @@
+//go:build example
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/metafates/testo"
+)
+
+type B = *testo.B
+
+func Benchmark(b *testing.B) {
+	testo.BenchSuite[*Suite, B](b)
+}
+
+type Suite struct {
+	words []string
+}
+
+func (s *Suite) BeforeAll(b B) {
+	s.words = strings.Fields(strings.Repeat("lorem ipsum dolor sit amet ", 100))
+}
+
+func (Suite) CasesSep() map[string]string {
+	return map[string]string{"space": " ", "comma": ","}
+}
+
+func (s *Suite) BenchmarkJoin(b B, params struct{ Sep string }) {
+	for range b.N {
+		_ = strings.Join(s.words, params.Sep)
+	}
+}
+
+func (s *Suite) BenchmarkBuilder(b B) {
+	for range b.N {
+		var sb strings.Builder
+
+		for _, w := range s.words {
+			sb.WriteString(w)
+		}
+
+		_ = sb.String()
+	}
+}
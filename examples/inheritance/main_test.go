@@ -6,13 +6,15 @@ import (
 	"testing"
 
 	"github.com/metafates/testo"
+	"github.com/metafates/testo/pkg/plugins/shuffle"
 )
 
 // This is the base T which contains common plugins and maybe add it's own methods
 type BaseT struct {
 	*testo.T
 
-	RandomizeOrder
+	// runs tests in random order with -testo.shuffle=on flag
+	shuffle.Shuffle
 }
 
 // This method will be available for [T] because it embeds [BaseT]
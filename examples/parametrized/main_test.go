@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/metafates/testo"
@@ -70,3 +71,51 @@ func (Suite) TestAdditions(t *T, params struct{ Add AddCase }) {
 		t.Error("%d + %d != %d", add.A, add.B, add.Want)
 	}
 }
+
+// Cases can be named, so that tests are named after them,
+// e.g. "TestTrim/leading_spaces" instead of "TestTrim_case_1".
+
+func (Suite) CasesInput() []testo.Case[string] {
+	return []testo.Case[string]{
+		{Name: "leading spaces", Value: "  foo"},
+		{Name: "trailing spaces", Value: "foo  "},
+		{Name: "tabs", Value: "\tfoo\t", Skip: "tabs are not supported yet"},
+	}
+}
+
+func (Suite) TestTrim(t *T, params struct{ Input string }) {
+	if strings.TrimSpace(params.Input) != "foo" {
+		t.Errorf("unexpected result for %q", params.Input)
+	}
+}
+
+// Strategies allow to run fewer combinations of cases.
+// TestFizz runs 8 combinations instead of 16, each pair of values is still tested.
+
+func (Suite) Strategies() map[string]testo.Strategy {
+	return map[string]testo.Strategy{
+		"TestFizz": testo.Pairwise(),
+	}
+}
+
+// Property tests check that something holds for many random inputs.
+// Params are generated by GenXXX methods instead of CasesXXX.
+
+func (Suite) GenWords() testo.Gen[[]string] {
+	return testo.GenSlice(testo.GenString(8), 5)
+}
+
+func (Suite) PropertyJoinSplit(t *T, params struct{ Words []string }) {
+	for _, w := range params.Words {
+		if w == "" || strings.Contains(w, ",") {
+			// discard inputs the property does not apply to
+			t.Skip()
+		}
+	}
+
+	joined := strings.Join(params.Words, ",")
+
+	if len(params.Words) > 0 && len(strings.Split(joined, ",")) != len(params.Words) {
+		t.Errorf("split of %q does not match %q", joined, params.Words)
+	}
+}
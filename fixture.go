@@ -0,0 +1,483 @@
+package testo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/internal/reflectutil"
+)
+
+// Scope defines lifetime of a fixture value.
+//
+// Scope of a fixture is declared by the suite "Scopes" method:
+//
+//	func (Suite) Scopes() map[string]testo.Scope {
+//		return map[string]testo.Scope{"DB": testo.ScopeSuite}
+//	}
+//
+// Fixtures not listed there have [ScopeTest].
+type Scope int
+
+const (
+	// ScopeTest fixture is created for each test which requires it
+	// and torn down when the test finishes.
+	ScopeTest Scope = iota
+
+	// ScopeSuite fixture is created once per suite, right after BeforeAll hook,
+	// and torn down after AfterAll hook.
+	ScopeSuite
+
+	// ScopeRun fixture is created once per test binary run
+	// and shared between all suites which provide it.
+	//
+	// It requires [Main] to be called from TestMain, which tears it down.
+	// The T given to its provider outlives the suite which requested it first,
+	// so its cleanups, temporary directories and context are bound to the run.
+	ScopeRun
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeTest:
+		return "test"
+
+	case ScopeSuite:
+		return "suite"
+
+	case ScopeRun:
+		return "run"
+
+	default:
+		return fmt.Sprintf("Scope(%d)", int(s))
+	}
+}
+
+// Main is a helper to be called from TestMain.
+// It runs the tests, tears down fixtures of [ScopeRun] and exits.
+//
+//	func TestMain(m *testing.M) {
+//		testo.Main(m)
+//	}
+//
+// Failures reported by fixtures of [ScopeRun] after their provider returned,
+// e.g. in cleanups, fail the run.
+func Main(m *testing.M) {
+	runFixtures.Start()
+
+	code := m.Run()
+
+	if !runFixtures.Release() && code == 0 {
+		code = 1
+	}
+
+	os.Exit(code)
+}
+
+// suiteFixture is a fixture provided by the suite method.
+//
+//	func (Suite) FixtureDB(t T, cfg *Config) *DB
+type suiteFixture[Suite any, T any] struct {
+	// Name of the fixture without "Fixture" prefix.
+	Name string
+
+	// Provides type of the fixture value.
+	Provides reflect.Type
+
+	Scope Scope
+
+	// Requires types of the other fixtures this fixture depends on.
+	Requires []reflect.Type
+
+	Func func(s Suite, t T, requires []reflect.Value) reflect.Value
+}
+
+// suiteFixtures are fixtures of the suite indexed by the type they provide.
+type suiteFixtures[Suite any, T CommonT] map[reflect.Type]suiteFixture[Suite, T]
+
+//nolint:cyclop,funlen // splitting it would make it even more complex
+func suiteFixturesOf[Suite any, T CommonT](t fataller) suiteFixtures[Suite, T] {
+	vt := reflect.TypeFor[Suite]()
+
+	scopes := suiteScopesOf[Suite](t)
+
+	fixtures := make(suiteFixtures[Suite, T])
+
+	for i := range vt.NumMethod() {
+		method := vt.Method(i)
+
+		const prefix = "Fixture"
+
+		if !isTest(method.Name, prefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(method.Name, prefix)
+
+		isValidIn := method.Type.NumIn() >= 2 && method.Type.In(1) == reflect.TypeFor[T]()
+		isValidOut := method.Type.NumOut() == 1
+
+		if !isValidIn || !isValidOut {
+			t.Fatalf(
+				"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s(%[3]s, ...) V",
+				vt, method.Name, reflect.TypeFor[T](),
+			)
+		}
+
+		provides := method.Type.Out(0)
+
+		if other, ok := fixtures[provides]; ok {
+			t.Fatalf(
+				"fixtures %[1]s.Fixture%[2]s and %[1]s.Fixture%[3]s provide the same type %[4]s",
+				vt, other.Name, name, provides,
+			)
+		}
+
+		requires := make([]reflect.Type, 0, method.Type.NumIn()-2)
+
+		for i := 2; i < method.Type.NumIn(); i++ {
+			requires = append(requires, method.Type.In(i))
+		}
+
+		fixtures[provides] = suiteFixture[Suite, T]{
+			Name:     name,
+			Provides: provides,
+			Scope:    scopes[name],
+			Requires: requires,
+			Func: func(s Suite, t T, requires []reflect.Value) reflect.Value {
+				args := append([]reflect.Value{reflect.ValueOf(s), reflect.ValueOf(t)}, requires...)
+
+				return method.Func.Call(args)[0]
+			},
+		}
+
+		delete(scopes, name)
+	}
+
+	if len(scopes) > 0 {
+		unknown := maputil.Keys(scopes)
+		slices.Sort(unknown)
+
+		t.Fatalf(
+			"wrong scopes for %[1]s: fixtures %[2]s not found",
+			vt, strings.Join(unknown, ", "),
+		)
+	}
+
+	fixtures.validate(t)
+
+	return fixtures
+}
+
+// suiteScopesOf returns fixture scopes declared by the "Scopes" suite method.
+func suiteScopesOf[Suite any, T fataller](t T) map[string]Scope {
+	const name = "Scopes"
+
+	method, ok := reflect.TypeFor[Suite]().MethodByName(name)
+	if !ok {
+		return make(map[string]Scope)
+	}
+
+	f, ok := method.Func.Interface().(func(Suite) map[string]Scope)
+	if !ok {
+		t.Fatalf(
+			"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s() map[string]%[3]s",
+			reflect.TypeFor[Suite](), name, reflect.TypeFor[Scope](),
+		)
+
+		return nil
+	}
+
+	scopes := make(map[string]Scope)
+
+	for name, scope := range f(reflectutil.Make[Suite]()) {
+		scopes[name] = scope
+	}
+
+	return scopes
+}
+
+// validate checks that fixture dependencies exist,
+// do not form cycles and do not outlive the fixtures they depend on.
+func (f suiteFixtures[Suite, T]) validate(t fataller) {
+	suite := reflect.TypeFor[Suite]()
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := make(map[reflect.Type]int, len(f))
+
+	var visit func(fixture suiteFixture[Suite, T], path []string)
+
+	visit = func(fixture suiteFixture[Suite, T], path []string) {
+		path = append(path, fixture.Name)
+
+		switch state[fixture.Provides] {
+		case visited:
+			return
+
+		case visiting:
+			t.Fatalf(
+				"fixtures of %s form a cycle: %s",
+				suite, strings.Join(path, " -> "),
+			)
+
+			return
+		}
+
+		state[fixture.Provides] = visiting
+
+		for _, typ := range fixture.Requires {
+			dep, ok := f[typ]
+			if !ok {
+				t.Fatalf(
+					"wrong signature for %[1]s.Fixture%[2]s: no fixture provides %[3]s",
+					suite, fixture.Name, typ,
+				)
+
+				return
+			}
+
+			if dep.Scope < fixture.Scope {
+				t.Fatalf(
+					"fixture %[1]s.Fixture%[2]s of scope %[3]s can not depend on fixture %[1]s.Fixture%[4]s of scope %[5]s",
+					suite, fixture.Name, fixture.Scope, dep.Name, dep.Scope,
+				)
+			}
+
+			visit(dep, path)
+		}
+
+		state[fixture.Provides] = visited
+	}
+
+	for _, typ := range f.sortedTypes() {
+		visit(f[typ], nil)
+	}
+}
+
+// sortedTypes returns provided types sorted by the fixture names
+// for determenistic processing order.
+func (f suiteFixtures[Suite, T]) sortedTypes() []reflect.Type {
+	types := maputil.Keys(f)
+
+	slices.SortFunc(types, func(a, b reflect.Type) int {
+		return strings.Compare(f[a].Name, f[b].Name)
+	})
+
+	return types
+}
+
+// closure returns given types along with all their transitive dependencies.
+func (f suiteFixtures[Suite, T]) closure(types []reflect.Type) []reflect.Type {
+	seen := make(map[reflect.Type]struct{})
+
+	var result []reflect.Type
+
+	var visit func(typ reflect.Type)
+
+	visit = func(typ reflect.Type) {
+		if _, ok := seen[typ]; ok {
+			return
+		}
+
+		seen[typ] = struct{}{}
+
+		for _, dep := range f[typ].Requires {
+			visit(dep)
+		}
+
+		result = append(result, typ)
+	}
+
+	for _, typ := range types {
+		visit(typ)
+	}
+
+	return result
+}
+
+// Setup resolves fixtures of [ScopeSuite] and [ScopeRun]
+// which are required (directly or not) by the given types.
+//
+// Given t must be the top-level suite T.
+func (f suiteFixtures[Suite, T]) Setup(t T, s Suite, types []reflect.Type) {
+	t.Helper()
+
+	for _, typ := range f.closure(types) {
+		fixture := f[typ]
+
+		switch fixture.Scope {
+		case ScopeTest:
+			continue
+
+		case ScopeSuite:
+			requires := f.requires(t, s, fixture)
+
+			t.unwrap().setFixture(typ, fixture.Func(s, t, requires))
+
+		case ScopeRun:
+			requires := f.requires(t, s, fixture)
+
+			value, ok := runFixtures.Get(runFixtureKey{Name: fixture.Name, Type: typ}, func(l *lifetime) reflect.Value {
+				// The value outlives the suite, so is the T given to the provider.
+				runT := construct[T](
+					t.unwrap().T,
+					nil,
+					func(runT *actualT) {
+						runT.suiteName = t.unwrap().SuiteName()
+						runT.lifetime = l
+					},
+					t.unwrap().levelOptions...,
+				)
+
+				defer runT.unwrap().detach(&runFixtures)
+
+				return fixture.Func(s, runT, requires)
+			})
+			if !ok {
+				t.Fatalf(
+					"fixture %s.Fixture%s of scope %s requires testo.Main to be called from TestMain",
+					reflect.TypeFor[Suite](), fixture.Name, fixture.Scope,
+				)
+			}
+
+			t.unwrap().setFixture(typ, value)
+		}
+	}
+}
+
+// Resolve value of the fixture providing given type.
+//
+// Fixtures of [ScopeTest] are created on demand and cached for the given t.
+// Other fixtures must be resolved with [suiteFixtures.Setup] beforehand.
+func (f suiteFixtures[Suite, T]) Resolve(t T, s Suite, typ reflect.Type) reflect.Value {
+	t.Helper()
+
+	if value, ok := t.unwrap().fixture(typ); ok {
+		return value
+	}
+
+	fixture := f[typ]
+
+	if fixture.Scope != ScopeTest {
+		panic(fmt.Sprintf("fixture %s of scope %s is not set up", fixture.Name, fixture.Scope))
+	}
+
+	value := fixture.Func(s, t, f.requires(t, s, fixture))
+
+	t.unwrap().setFixture(typ, value)
+
+	return value
+}
+
+// requires resolves values of the fixtures required by the given fixture.
+func (f suiteFixtures[Suite, T]) requires(
+	t T,
+	s Suite,
+	fixture suiteFixture[Suite, T],
+) []reflect.Value {
+	t.Helper()
+
+	requires := make([]reflect.Value, 0, len(fixture.Requires))
+
+	for _, typ := range fixture.Requires {
+		requires = append(requires, f.Resolve(t, s, typ))
+	}
+
+	return requires
+}
+
+type runFixtureKey struct {
+	Name string
+	Type reflect.Type
+}
+
+// runFixtureRegistry stores fixtures of [ScopeRun].
+type runFixtureRegistry struct {
+	mu     sync.Mutex
+	values map[runFixtureKey]reflect.Value
+
+	// lifetime of the run, nil unless started by [Main].
+	lifetime *lifetime
+
+	// failed states that detached T of some fixture has failed.
+	failed atomic.Bool
+}
+
+//nolint:gochecknoglobals // fixtures of run scope are global by definition.
+var runFixtures = runFixtureRegistry{
+	values: make(map[runFixtureKey]reflect.Value),
+}
+
+// Start the run, so that fixtures could be created.
+func (r *runFixtureRegistry) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lifetime = newLifetime(context.Background())
+	r.failed.Store(false)
+}
+
+// Get returns the value for the given key, creating it with make if needed.
+// It reports false if the run is not started.
+func (r *runFixtureRegistry) Get(
+	key runFixtureKey,
+	make func(l *lifetime) reflect.Value,
+) (reflect.Value, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.lifetime == nil {
+		return reflect.Value{}, false
+	}
+
+	if value, ok := r.values[key]; ok {
+		return value, true
+	}
+
+	value := make(r.lifetime)
+
+	r.values[key] = value
+
+	return value, true
+}
+
+// Log the message of detached T, see [T.detach].
+func (r *runFixtureRegistry) Log(args ...any) {
+	fmt.Fprintln(os.Stderr, append([]any{"testo: run fixture:"}, args...)...)
+}
+
+// Error logs the failure of detached T, see [T.detach].
+//
+// Failures make [runFixtureRegistry.Release] report false.
+func (r *runFixtureRegistry) Error(args ...any) {
+	r.failed.Store(true)
+
+	r.Log(args...)
+}
+
+// Release ends the run lifetime and forgets all values.
+// It reports whether no detached T has failed.
+func (r *runFixtureRegistry) Release() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.lifetime != nil {
+		r.lifetime.End()
+		r.lifetime = nil
+	}
+
+	clear(r.values)
+
+	return !r.failed.Load()
+}
@@ -0,0 +1,58 @@
+package testo
+
+import "flag"
+
+//nolint:gochecknoglobals // flags can be global
+var retriesFlag = flag.Int(
+	"testo.retries",
+	0,
+	"number of times to retry failed suite tests, overrides retry policy option when set, including 0",
+)
+
+//nolint:gochecknoglobals // flags can be global
+var seedFlag = flag.Uint64(
+	"testo.seed",
+	0,
+	"seed for generating inputs of property tests, random when zero",
+)
+
+//nolint:gochecknoglobals // flags can be global
+var iterationsFlag = flag.Int(
+	"testo.iterations",
+	100,
+	"number of inputs to check for each property test",
+)
+
+//nolint:gochecknoglobals // flags can be global
+var tagsFlag = flag.String(
+	"testo.tags",
+	"",
+	`boolean expression of tags to filter suite tests by, e.g. "integration && !slow"`,
+)
+
+//nolint:gochecknoglobals // flags can be global
+var failOnFocusFlag = flag.Bool(
+	"testo.fail-on-focus",
+	false,
+	"fail suites with focused tests, e.g. to prevent committing them",
+)
+
+//nolint:gochecknoglobals // flags can be global
+var shardFlag = flag.String(
+	"testo.shard",
+	"",
+	`run only the given shard of suite tests, e.g. "2/8" for the second of eight shards`,
+)
+
+// isFlagSet reports whether the flag with the given name was set in the flag set.
+func isFlagSet(set *flag.FlagSet, name string) bool {
+	var ok bool
+
+	set.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			ok = true
+		}
+	})
+
+	return ok
+}
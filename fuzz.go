@@ -0,0 +1,490 @@
+package testo
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metafates/testo/internal/reflectutil"
+	"github.com/metafates/testo/plugin"
+)
+
+// FuzzSuite will fuzz the given suite.
+//
+// The suite must have a method in the form of:
+//
+//	func (Suite) FuzzFoo(t T, params struct{ ... }, [fixtures...])
+//
+// Params fields are fuzzing arguments and must be of the types supported by [testing.F.Fuzz].
+// If the suite has multiple such methods, the one named as the fuzz test is used:
+//
+//	func FuzzFoo(f *testing.F) {
+//		testo.FuzzSuite[*Suite, *T](f)
+//	}
+//
+// CasesXXX methods for the params are used as the seed corpus.
+// Values of all the cases are combined, params without cases are zero.
+// Note that they are called before BeforeAll hook.
+//
+// The suite is set up once: BeforeAll hooks are run and suite fixtures are provided
+// before the first input, AfterAll hooks are run after all of them.
+// Each input is run as a suite test, with BeforeEach and AfterEach hooks
+// and test fixtures of its own.
+func FuzzSuite[Suite any, T CommonT](f *testing.F, options ...plugin.Option) {
+	f.Helper()
+
+	checkSuiteType[Suite]()
+
+	options = append(getDefaultOptions(), options...)
+
+	fixtures := suiteFixturesOf[Suite, T](f)
+
+	method, args := fuzzMethodOf(f, fixtures)
+
+	param := method.Type.In(args.Params)
+
+	for i := range param.NumField() {
+		field := param.Field(i)
+
+		if !isFuzzType(field.Type) {
+			f.Fatalf(
+				"wrong param signature for %[1]s.%[2]s: param %[3]q of type %[4]s is not supported by fuzzing",
+				reflect.TypeFor[Suite](),
+				method.Name,
+				field.Name,
+				field.Type,
+			)
+		}
+	}
+
+	addFuzzSeeds[Suite](f, method.Name, param, suiteCasesOf[Suite](f))
+
+	tags := suiteTagsOf[Suite](f)
+
+	// fuzz inputs are not sharded, since they are not known beforehand
+	plans := []plugin.Plan{tagsPlanOf(f)}
+
+	var requiredFixtures []reflect.Type
+
+	for _, typ := range fixtures.sortedTypes() {
+		for _, required := range args.Fixtures {
+			if typ == required {
+				requiredFixtures = append(requiredFixtures, typ)
+
+				break
+			}
+		}
+	}
+
+	in := []reflect.Type{reflect.TypeFor[*testing.T]()}
+
+	for i := range param.NumField() {
+		in = append(in, param.Field(i).Type)
+	}
+
+	var (
+		setUp    sync.Once
+		suite    *fuzzedSuite[Suite, T]
+		reporter = &fuzzReporter{f: f}
+	)
+
+	f.Cleanup(func() {
+		if suite != nil {
+			suite.lifetime.End()
+		}
+	})
+
+	fuzz := reflect.MakeFunc(reflect.FuncOf(in, nil, false), func(in []reflect.Value) []reflect.Value {
+		//nolint:forcetypeassert // type is defined above
+		rawT := in[0].Interface().(*testing.T)
+
+		reporter.Enter(rawT)
+		defer reporter.Leave()
+
+		setUp.Do(func() {
+			suite = newFuzzedSuite[Suite, T](rawT, f.Name(), options...)
+
+			// the suite outlives the input it is set up by
+			defer suite.t.unwrap().detach(reporter)
+
+			suite.SetUp(fixtures, requiredFixtures)
+		})
+
+		if !suite.isSetUp {
+			rawT.Skip("suite setup has failed")
+		}
+
+		paramValue := reflect.New(param).Elem()
+		params := make(map[string]any, param.NumField())
+
+		for i := range param.NumField() {
+			paramValue.Field(i).Set(in[i+1])
+
+			params[param.Field(i).Name] = in[i+1].Interface()
+		}
+
+		test := suiteTest[Suite, T]{
+			Name: method.Name,
+			Info: plugin.ParametrizedTestInfo{
+				RawBaseName: method.Name,
+				Params:      params,
+			},
+			Run: func(s Suite, t T) {
+				callTest(method, args, s, t, paramValue, fixtures)
+			},
+		}
+
+		suite.Run(rawT, withTags(test, tags[method.Name]), plans)
+
+		return nil
+	})
+
+	f.Fuzz(fuzz.Interface())
+
+	reporter.Flush()
+}
+
+// fuzzReporter receives logs and failures of the fuzzed suite T, see [T.detach].
+//
+// [testing.F] must not be used inside the fuzz target,
+// so they are given to the input being run instead.
+// Those reported between the inputs are buffered until fuzzing is done.
+type fuzzReporter struct {
+	f *testing.F
+
+	mu sync.Mutex
+
+	// input being run, if any.
+	input *testing.T
+
+	// done states that fuzzing is done, so that [testing.F] can be used.
+	done bool
+
+	buffered []fuzzReport
+}
+
+type fuzzReport struct {
+	args   []any
+	failed bool
+}
+
+// Enter is called before the input is run.
+func (r *fuzzReporter) Enter(input *testing.T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.input = input
+}
+
+// Leave is called after the input is run.
+func (r *fuzzReporter) Leave() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.input = nil
+}
+
+// Flush gives the buffered reports to [testing.F].
+// It must be called after fuzzing is done.
+func (r *fuzzReporter) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.done = true
+
+	for _, report := range r.buffered {
+		r.report(report)
+	}
+
+	r.buffered = nil
+}
+
+// Log the message of detached T.
+func (r *fuzzReporter) Log(args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.report(fuzzReport{args: args})
+}
+
+// Error logs the failure of detached T.
+func (r *fuzzReporter) Error(args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.report(fuzzReport{args: args, failed: true})
+}
+
+func (r *fuzzReporter) report(report fuzzReport) {
+	var to reporter
+
+	switch {
+	case r.input != nil:
+		to = r.input
+
+	case r.done:
+		to = r.f
+
+	default:
+		r.buffered = append(r.buffered, report)
+
+		return
+	}
+
+	if report.failed {
+		to.Error(report.args...)
+	} else {
+		to.Log(report.args...)
+	}
+}
+
+// fuzzedSuite is the suite shared by all inputs of [FuzzSuite].
+//
+// It is set up by the first input and torn down after all of them,
+// so that BeforeAll hooks and suite fixtures are not run for each input.
+type fuzzedSuite[Suite any, T CommonT] struct {
+	t        T
+	suite    Suite
+	hooks    suiteHooks[Suite, T]
+	outcomes *testOutcomes
+	retry    RetryPolicy
+	timeout  time.Duration
+
+	// lifetime of the suite, ended after all inputs.
+	lifetime *lifetime
+
+	// isSetUp states that BeforeAll hooks and fixtures setup have not failed.
+	isSetUp bool
+}
+
+func newFuzzedSuite[Suite any, T CommonT](
+	rawT *testing.T,
+	name string,
+	options ...plugin.Option,
+) *fuzzedSuite[Suite, T] {
+	s := &fuzzedSuite[Suite, T]{
+		suite:    reflectutil.Make[Suite](),
+		outcomes: newTestOutcomes(),
+		lifetime: newLifetime(context.Background()),
+	}
+
+	s.t = construct[T](
+		rawT,
+		nil,
+		func(t *actualT) {
+			t.suiteName = reflectutil.NameOf[Suite]()
+			t.testName = name
+			t.lifetime = s.lifetime
+		},
+		options...,
+	)
+
+	s.hooks = suiteHooksOf[Suite](s.t)
+	s.retry = retryPolicyOf(s.t.unwrap().options())
+	s.timeout = timeoutOf(s.t.unwrap().options())
+
+	return s
+}
+
+// SetUp runs suite and plugin BeforeAll hooks and sets up the fixtures.
+// The suite is torn down when its lifetime ends.
+func (s *fuzzedSuite[Suite, T]) SetUp(fixtures suiteFixtures[Suite, T], required []reflect.Type) {
+	t := s.t
+
+	t.Helper()
+
+	s.lifetime.Cleanup(func() { t.unwrap().finishSuite(s.outcomes) })
+
+	t.unwrap().emit(func(meta plugin.EventMeta) plugin.Event {
+		return plugin.SuiteStarted{
+			EventMeta: meta,
+			Suite:     t.unwrap().SuiteName(),
+			SuiteType: reflect.TypeFor[Suite](),
+		}
+	})
+
+	t.unwrap().plugin.Hooks.BeforeAll.Run()
+	s.hooks.BeforeAll(s.suite, t)
+
+	s.lifetime.Cleanup(func() {
+		s.hooks.AfterAll(s.suite, t)
+		t.unwrap().plugin.Hooks.AfterAll.Run()
+	})
+
+	fixtures.Setup(t, s.suite, required)
+
+	s.isSetUp = true
+}
+
+// Run the test of the fuzz input with the given rawT.
+func (s *fuzzedSuite[Suite, T]) Run(rawT *testing.T, test suiteTest[Suite, T], plans []plugin.Plan) {
+	planned := applyPlan(
+		[]suiteTest[Suite, T]{test},
+		append(slices.Clone(plans), s.t.unwrap().plugin.Plan)...,
+	)
+
+	for _, test := range planned {
+		s.t.unwrap().emit(func(meta plugin.EventMeta) plugin.Event {
+			return plugin.TestPlanned{EventMeta: meta, Name: test.Name, Info: test.Info, Skip: test.Skip}
+		})
+
+		func() {
+			defer s.outcomes.Record(rawBaseNameOf(test.Info), rawT)
+
+			runSuiteTestAttempts(
+				rawT,
+				s.t,
+				s.suite,
+				s.hooks,
+				test,
+				s.retry,
+				cmp.Or(test.Timeout, s.timeout),
+			)
+		}()
+	}
+}
+
+// fuzzMethodOf returns the fuzz method of the suite to be used for the given f.
+func fuzzMethodOf[Suite any, T CommonT](
+	f *testing.F,
+	fixtures suiteFixtures[Suite, T],
+) (reflect.Method, testArgs) {
+	vt := reflect.TypeFor[Suite]()
+
+	var candidates []reflect.Method
+
+	for i := range vt.NumMethod() {
+		if method := vt.Method(i); isTest(method.Name, "Fuzz") {
+			candidates = append(candidates, method)
+		}
+	}
+
+	method, ok := vt.MethodByName(f.Name())
+	if !ok || !isTest(method.Name, "Fuzz") {
+		if len(candidates) != 1 {
+			names := make([]string, 0, len(candidates))
+
+			for _, c := range candidates {
+				names = append(names, c.Name)
+			}
+
+			f.Fatalf(
+				"fuzz method %[1]s.%[2]s not found, suite has %[3]d fuzz methods: %[4]s",
+				vt, f.Name(), len(candidates), strings.Join(names, ", "),
+			)
+
+			return reflect.Method{}, testArgs{}
+		}
+
+		method = candidates[0]
+	}
+
+	args, missing, ok := testArgsOf(method, fixtures)
+	if missing != nil {
+		f.Fatalf(
+			"wrong signature for %[1]s.%[2]s: no fixture provides %[3]s",
+			vt, method.Name, missing,
+		)
+	}
+
+	if !ok || args.Params == -1 {
+		f.Fatalf(
+			"wrong signature for %[1]s.%[2]s, must be: func %[1]s.%[2]s(%[3]s, struct{...}, [fixtures...])",
+			vt,
+			method.Name,
+			reflect.TypeFor[T](),
+		)
+	}
+
+	return method, args
+}
+
+// addFuzzSeeds adds combinations of the params cases to the seed corpus.
+func addFuzzSeeds[Suite any](
+	f *testing.F,
+	name string,
+	param reflect.Type,
+	cases map[string]suiteCase[Suite],
+) {
+	f.Helper()
+
+	suite := reflectutil.Make[Suite]()
+
+	values := make(map[string][]suiteCaseValue)
+
+	for i := range param.NumField() {
+		field := param.Field(i)
+
+		c, ok := cases[field.Name]
+		if !ok {
+			continue
+		}
+
+		c = c.resolve(f, name, field.Name, field.Type)
+
+		if !c.Provides.AssignableTo(field.Type) {
+			f.Fatalf(
+				"wrong param signature for %[1]s.%[2]s: Cases%[3]s provides %[4]s values, not assignable to param %[3]q of type %[5]s",
+				reflect.TypeFor[Suite](),
+				name,
+				field.Name,
+				c.Provides,
+				field.Type,
+			)
+		}
+
+		values[field.Name] = c.Func(suite)
+	}
+
+	if len(values) == 0 {
+		return
+	}
+
+	for _, combination := range casesPermutations(values) {
+		seed := make([]any, 0, param.NumField())
+
+		for i := range param.NumField() {
+			field := param.Field(i)
+
+			value := reflect.New(field.Type).Elem()
+
+			if c, ok := combination[field.Name]; ok {
+				value.Set(c.Value)
+			}
+
+			seed = append(seed, value.Interface())
+		}
+
+		f.Add(seed...)
+	}
+}
+
+// isFuzzType states whether values of the given type can be fuzzed.
+func isFuzzType(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeFor[string](),
+		reflect.TypeFor[[]byte](),
+		reflect.TypeFor[bool](),
+		reflect.TypeFor[float32](),
+		reflect.TypeFor[float64](),
+		reflect.TypeFor[int](),
+		reflect.TypeFor[int8](),
+		reflect.TypeFor[int16](),
+		reflect.TypeFor[int32](),
+		reflect.TypeFor[int64](),
+		reflect.TypeFor[uint](),
+		reflect.TypeFor[uint8](),
+		reflect.TypeFor[uint16](),
+		reflect.TypeFor[uint32](),
+		reflect.TypeFor[uint64]():
+		return true
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,230 @@
+package testo
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+)
+
+// Gen generates random values of type V for property tests.
+//
+// Generators are provided by the suite GenXXX methods,
+// where XXX is the name of the param they generate:
+//
+//	func (Suite) GenAmount() testo.Gen[int] {
+//		return testo.GenInt(0, 1000)
+//	}
+type Gen[V any] struct {
+	// Generate returns a random value using the given source of randomness.
+	Generate func(r *rand.Rand) V
+
+	// Shrink returns values simpler than the given one, simplest first.
+	// It is used to find a minimal input which falsifies the property.
+	//
+	// It may be nil if values can not be shrunk.
+	Shrink func(v V) []V
+}
+
+// anyGen is implemented by any [Gen].
+type anyGen interface {
+	valueType() reflect.Type
+	generate(r *rand.Rand) reflect.Value
+	shrink(v reflect.Value) []reflect.Value
+}
+
+func (Gen[V]) valueType() reflect.Type {
+	return reflect.TypeFor[V]()
+}
+
+func (g Gen[V]) generate(r *rand.Rand) reflect.Value {
+	v := g.Generate(r)
+
+	return reflect.ValueOf(&v).Elem()
+}
+
+func (g Gen[V]) shrink(v reflect.Value) []reflect.Value {
+	if g.Shrink == nil {
+		return nil
+	}
+
+	// nil interface values are not asserted, zero value is used instead
+	value, _ := v.Interface().(V)
+
+	candidates := g.Shrink(value)
+
+	values := make([]reflect.Value, 0, len(candidates))
+
+	for _, c := range candidates {
+		values = append(values, reflect.ValueOf(&c).Elem())
+	}
+
+	return values
+}
+
+// GenInt generates integers in the range [lo, hi].
+//
+// Values are shrunk towards zero, or the bound closest to it.
+func GenInt(lo, hi int) Gen[int] {
+	if lo > hi {
+		panic("testo: GenInt requires lo <= hi")
+	}
+
+	target := min(max(0, lo), hi)
+
+	// span is computed in unsigned integers,
+	// so that it does not overflow for the full range of int.
+	span := uint64(hi) - uint64(lo)
+
+	return Gen[int]{
+		Generate: func(r *rand.Rand) int {
+			if span == math.MaxUint64 {
+				return int(r.Uint64())
+			}
+
+			return lo + int(r.Uint64N(span+1))
+		},
+		Shrink: func(v int) []int {
+			var candidates []int
+
+			for diff := v - target; diff != 0; diff /= 2 {
+				candidates = append(candidates, v-diff)
+			}
+
+			return candidates
+		},
+	}
+}
+
+// GenBool generates booleans.
+//
+// Values are shrunk towards false.
+func GenBool() Gen[bool] {
+	return Gen[bool]{
+		Generate: func(r *rand.Rand) bool {
+			return r.IntN(2) == 1
+		},
+		Shrink: func(v bool) []bool {
+			if v {
+				return []bool{false}
+			}
+
+			return nil
+		},
+	}
+}
+
+// GenOneOf generates one of the given values.
+//
+// Values are shrunk towards the ones given first.
+func GenOneOf[V any](values ...V) Gen[V] {
+	if len(values) == 0 {
+		panic("testo: GenOneOf requires at least one value")
+	}
+
+	return Gen[V]{
+		Generate: func(r *rand.Rand) V {
+			return values[r.IntN(len(values))]
+		},
+		Shrink: func(v V) []V {
+			for i, value := range values {
+				if reflect.DeepEqual(value, v) {
+					return values[:i]
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// GenString generates strings of printable ASCII characters
+// with length up to maxLen.
+//
+// Strings are shrunk by removing characters and replacing them with 'a'.
+func GenString(maxLen int) Gen[string] {
+	chars := GenInt(' ', '~')
+
+	elems := Gen[byte]{
+		Generate: func(r *rand.Rand) byte {
+			return byte(chars.Generate(r))
+		},
+		Shrink: func(v byte) []byte {
+			if v == 'a' {
+				return nil
+			}
+
+			return []byte{'a'}
+		},
+	}
+
+	bytes := GenSlice(elems, maxLen)
+
+	return Gen[string]{
+		Generate: func(r *rand.Rand) string {
+			return string(bytes.Generate(r))
+		},
+		Shrink: func(v string) []string {
+			candidates := bytes.Shrink([]byte(v))
+
+			result := make([]string, 0, len(candidates))
+
+			for _, c := range candidates {
+				result = append(result, string(c))
+			}
+
+			return result
+		},
+	}
+}
+
+// GenSlice generates slices with length up to maxLen
+// and elements generated by the given generator.
+//
+// Slices are shrunk by removing elements, then by shrinking the elements.
+func GenSlice[V any](elem Gen[V], maxLen int) Gen[[]V] {
+	if maxLen < 0 {
+		panic("testo: GenSlice requires maxLen >= 0")
+	}
+
+	return Gen[[]V]{
+		Generate: func(r *rand.Rand) []V {
+			s := make([]V, r.IntN(maxLen+1))
+
+			for i := range s {
+				s[i] = elem.Generate(r)
+			}
+
+			return s
+		},
+		Shrink: func(v []V) [][]V {
+			var candidates [][]V
+
+			// remove chunks of decreasing size, starting from the whole slice
+			for size := len(v); size > 0; size /= 2 {
+				for start := 0; start+size <= len(v); start += size {
+					c := make([]V, 0, len(v)-size)
+					c = append(c, v[:start]...)
+					c = append(c, v[start+size:]...)
+
+					candidates = append(candidates, c)
+				}
+			}
+
+			if elem.Shrink == nil {
+				return candidates
+			}
+
+			for i := range v {
+				for _, e := range elem.Shrink(v[i]) {
+					c := make([]V, len(v))
+					copy(c, v)
+					c[i] = e
+
+					candidates = append(candidates, c)
+				}
+			}
+
+			return candidates
+		},
+	}
+}
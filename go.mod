@@ -2,15 +2,12 @@ module github.com/metafates/testo
 
 go 1.22
 
-// this is for example plugins only, could be removed
-// when plugins are moved in separate packages
+// these are for plugins, optional packages under pkg and tests only,
+// testo package itself depends on the standard library
 require (
+	github.com/davecgh/go-spew v1.1.1
 	github.com/google/uuid v1.6.0
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/stretchr/testify v1.10.0
-)
-
-require (
-	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,212 @@
+// Package eventtree builds the tree of tests from the events of the suite run.
+package eventtree
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/metafates/testo/plugin"
+)
+
+// Test is a node of the tree: the suite, its test or a subtest.
+type Test struct {
+	// ID of the test, see [plugin.TestID].
+	ID int
+
+	// Name is the full name of the test.
+	Name string
+
+	// BaseName is the name of the test relative to its parent.
+	BaseName string
+
+	// Info about the test.
+	// It is nil for the suite.
+	Info plugin.TestInfo
+
+	// Attempt of the suite test, see [plugin.TestID].
+	Attempt int
+
+	Parent *Test
+
+	// Children are the tests started by this one, in order.
+	// Only the last attempt of the retried test is included.
+	Children []*Test
+
+	// Retries are the previous attempts of this test.
+	Retries []*Test
+
+	// Logs of the test, in order.
+	Logs []string
+
+	// Failures are the messages of failures, in order.
+	Failures []string
+
+	Started  time.Time
+	Finished time.Time
+
+	// Result of the test.
+	// It is zero until the test is finished.
+	Result plugin.Result
+
+	// IsFinished states whether the test has finished.
+	IsFinished bool
+
+	// IsSkipped states whether the test was skipped.
+	IsSkipped bool
+}
+
+// Failed states whether the test has failed.
+func (t *Test) Failed() bool {
+	return t.Result.FailureKind != plugin.TestFailureKindNone
+}
+
+// IsPending states whether the test is declared pending.
+func (t *Test) IsPending() bool {
+	info, ok := t.Info.(plugin.RegularTestInfo)
+
+	return ok && info.Pending
+}
+
+// RawBaseName returns the name of the test as it was given, e.g. to testo.Run.
+func (t *Test) RawBaseName() string {
+	if info, ok := t.Info.(plugin.RegularTestInfo); ok {
+		return info.RawBaseName
+	}
+
+	return t.BaseName
+}
+
+// CaseName returns the name of the parametrized test case within its test.
+// The test must be parametrized.
+func (t *Test) CaseName() string {
+	//nolint:forcetypeassert // cases are always parametrized
+	info := t.Info.(plugin.ParametrizedTestInfo)
+
+	if info.CaseName != "" {
+		return info.CaseName
+	}
+
+	return strings.TrimPrefix(t.BaseName, info.RawBaseName+"_")
+}
+
+// Tree of the tests of the suite run.
+type Tree struct {
+	// Suite is the root of the tree.
+	// It is nil until the suite is started.
+	Suite *Test
+
+	// SuiteName is the name of the suite type.
+	SuiteName string
+
+	// SuiteType is the type of the suite, see [plugin.SuiteStarted].
+	SuiteType reflect.Type
+
+	// Planned tests of the suite, in order.
+	Planned []plugin.TestPlanned
+
+	byID map[int]*Test
+}
+
+// New returns an empty tree.
+func New() *Tree {
+	return &Tree{byID: make(map[int]*Test)}
+}
+
+// Test returns the test with the given ID.
+func (t *Tree) Test(id int) (*Test, bool) {
+	test, ok := t.byID[id]
+
+	return test, ok
+}
+
+// Add the event to the tree.
+// Events must be added in the order they were reported.
+func (t *Tree) Add(event plugin.Event) {
+	meta := event.Meta()
+
+	switch e := event.(type) {
+	case plugin.SuiteStarted:
+		t.SuiteName = e.Suite
+		t.SuiteType = e.SuiteType
+		t.Suite = &Test{
+			ID:       meta.Test.ID,
+			Name:     meta.Test.Name,
+			BaseName: e.Suite,
+			Started:  meta.Time,
+		}
+
+		t.byID[meta.Test.ID] = t.Suite
+
+	case plugin.TestPlanned:
+		t.Planned = append(t.Planned, e)
+
+	case plugin.TestStarted:
+		t.start(meta)
+
+	case plugin.Log:
+		if test, ok := t.byID[meta.Test.ID]; ok {
+			test.Logs = append(test.Logs, e.Message)
+		}
+
+	case plugin.Failure:
+		if test, ok := t.byID[meta.Test.ID]; ok && e.Message != "" {
+			test.Failures = append(test.Failures, e.Message)
+		}
+
+	case plugin.TestFinished:
+		if test, ok := t.byID[meta.Test.ID]; ok {
+			// params of property tests are set while the test runs
+			test.Info = meta.Test.Info
+			test.finish(meta.Time, e.Result)
+			test.IsSkipped = e.Skipped
+		}
+
+	case plugin.SuiteFinished:
+		if t.Suite != nil {
+			t.Suite.finish(meta.Time, e.Result)
+		}
+	}
+}
+
+func (t *Tree) start(meta plugin.EventMeta) {
+	parent, ok := t.byID[meta.Test.ParentID]
+	if !ok {
+		return
+	}
+
+	test := &Test{
+		ID:       meta.Test.ID,
+		Name:     meta.Test.Name,
+		BaseName: strings.TrimPrefix(meta.Test.Name, parent.Name+"/"),
+		Info:     meta.Test.Info,
+		Attempt:  meta.Test.Attempt,
+		Parent:   parent,
+		Started:  meta.Time,
+	}
+
+	t.byID[test.ID] = test
+
+	if test.Attempt > 1 && parent == t.Suite {
+		for i, previous := range parent.Children {
+			if previous.Name != test.Name {
+				continue
+			}
+
+			test.Retries = append(previous.Retries, previous)
+			previous.Retries = nil
+
+			parent.Children[i] = test
+
+			return
+		}
+	}
+
+	parent.Children = append(parent.Children, test)
+}
+
+func (t *Test) finish(at time.Time, result plugin.Result) {
+	t.Finished = at
+	t.Result = result
+	t.IsFinished = true
+}
@@ -0,0 +1,83 @@
+package eventtree
+
+import (
+	"testing"
+
+	"github.com/metafates/testo/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func meta(id, parentID, attempt int, name string) plugin.EventMeta {
+	return plugin.EventMeta{
+		Test: plugin.TestID{ID: id, ParentID: parentID, Name: name, Attempt: attempt},
+	}
+}
+
+func TestTree_Add(t *testing.T) {
+	tree := New()
+
+	for _, event := range []plugin.Event{
+		plugin.SuiteStarted{EventMeta: meta(0, 0, 0, "Test/Suite"), Suite: "Suite"},
+		plugin.TestPlanned{EventMeta: meta(0, 0, 0, "Test/Suite"), Name: "TestFoo"},
+		plugin.TestStarted{EventMeta: meta(1, 0, 1, "Test/Suite/TestFoo")},
+		plugin.Failure{EventMeta: meta(1, 0, 1, "Test/Suite/TestFoo"), Message: "first"},
+		plugin.TestFinished{
+			EventMeta: meta(1, 0, 1, "Test/Suite/TestFoo"),
+			Result:    plugin.Result{FailureKind: plugin.TestFailureKindSoft},
+		},
+		plugin.TestStarted{EventMeta: meta(2, 0, 2, "Test/Suite/TestFoo")},
+		plugin.TestStarted{EventMeta: meta(3, 2, 2, "Test/Suite/TestFoo/sub")},
+		plugin.Log{EventMeta: meta(3, 2, 2, "Test/Suite/TestFoo/sub"), Message: "hello"},
+		plugin.Skip{EventMeta: meta(3, 2, 2, "Test/Suite/TestFoo/sub"), Message: "later"},
+		plugin.TestFinished{EventMeta: meta(3, 2, 2, "Test/Suite/TestFoo/sub"), Skipped: true},
+		plugin.TestFinished{EventMeta: meta(2, 0, 2, "Test/Suite/TestFoo")},
+		plugin.SuiteFinished{EventMeta: meta(0, 0, 0, "Test/Suite")},
+	} {
+		tree.Add(event)
+	}
+
+	assert.Equal(t, "Suite", tree.SuiteName)
+	assert.Len(t, tree.Planned, 1)
+
+	require.Len(t, tree.Suite.Children, 1, "retries must replace previous attempts")
+
+	foo := tree.Suite.Children[0]
+
+	assert.Equal(t, 2, foo.Attempt)
+	assert.Equal(t, "TestFoo", foo.BaseName)
+	assert.False(t, foo.Failed())
+	assert.True(t, foo.IsFinished)
+
+	require.Len(t, foo.Retries, 1)
+	assert.Equal(t, []string{"first"}, foo.Retries[0].Failures)
+	assert.True(t, foo.Retries[0].Failed())
+
+	require.Len(t, foo.Children, 1)
+
+	sub := foo.Children[0]
+
+	assert.Equal(t, "sub", sub.BaseName)
+	assert.Same(t, foo, sub.Parent)
+	assert.Equal(t, []string{"hello"}, sub.Logs)
+	assert.True(t, sub.IsSkipped)
+
+	got, ok := tree.Test(3)
+	assert.True(t, ok)
+	assert.Same(t, sub, got)
+
+	assert.True(t, tree.Suite.IsFinished)
+}
+
+func TestTest_CaseName(t *testing.T) {
+	test := &Test{
+		BaseName: "TestFoo_case_1",
+		Info:     plugin.ParametrizedTestInfo{RawBaseName: "TestFoo"},
+	}
+
+	assert.Equal(t, "case_1", test.CaseName())
+
+	test.Info = plugin.ParametrizedTestInfo{RawBaseName: "TestFoo", CaseName: "admin"}
+
+	assert.Equal(t, "admin", test.CaseName())
+}
@@ -0,0 +1,38 @@
+// Package fsutil provides file system utilities.
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes data to the file at path, creating its directory if needed.
+//
+// Data is written to a temporary file first, which then replaces the file,
+// so readers never see a partially written file.
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,28 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a", "b.txt")
+
+	require.NoError(t, WriteFileAtomic(path, []byte("first")))
+	require.NoError(t, WriteFileAtomic(path, []byte("second")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "second", string(data))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+
+	assert.Len(t, entries, 1, "temporary files must be removed")
+}
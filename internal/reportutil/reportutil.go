@@ -0,0 +1,88 @@
+// Package reportutil provides utilities shared by the report plugins.
+package reportutil
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metafates/testo/internal/fsutil"
+	"github.com/metafates/testo/plugin"
+)
+
+// Files holds the data written to the report files, by their path.
+//
+// Suites of the package write to the same file,
+// so each suite adds its data to the one written before and rewrites the file.
+//
+// Zero value is ready to use.
+type Files[T any] struct {
+	mu sync.Mutex
+
+	data map[string]*T
+}
+
+// Write updates the data of the file at path and writes the file atomically.
+//
+// Update is given the data written to the file before, zero for a new file,
+// and returns the file contents.
+func (f *Files[T]) Write(path string, update func(data *T) ([]byte, error)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.data == nil {
+		f.data = make(map[string]*T)
+	}
+
+	data, ok := f.data[path]
+	if !ok {
+		data = new(T)
+		f.data[path] = data
+	}
+
+	contents, err := update(data)
+	if err != nil {
+		return err
+	}
+
+	return fsutil.WriteFileAtomic(path, contents)
+}
+
+// FormatDuration formats the duration for humans, e.g. "12ms" or "1.50s".
+func FormatDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
+
+// FailureKind returns the name of the failure kind: "soft", "fatal" or "timeout".
+// It is empty for [plugin.TestFailureKindNone].
+func FailureKind(kind plugin.TestFailureKind) string {
+	switch kind {
+	case plugin.TestFailureKindNone:
+		return ""
+
+	case plugin.TestFailureKindFatal:
+		return "fatal"
+
+	case plugin.TestFailureKindTimeout:
+		return "timeout"
+
+	default:
+		return "soft"
+	}
+}
+
+// FirstLine returns the first line of the first message.
+func FirstLine(messages []string) string {
+	if len(messages) == 0 {
+		return ""
+	}
+
+	line, _, _ := strings.Cut(messages[0], "\n")
+
+	return line
+}
@@ -0,0 +1,61 @@
+package reportutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metafates/testo/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiles_Write(t *testing.T) {
+	dir := t.TempDir()
+
+	var files Files[[]string]
+
+	write := func(path, line string) {
+		t.Helper()
+
+		require.NoError(t, files.Write(path, func(lines *[]string) ([]byte, error) {
+			*lines = append(*lines, line)
+
+			return []byte(strings.Join(*lines, "\n")), nil
+		}))
+	}
+
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+
+	write(a, "first")
+	write(b, "other")
+	write(a, "second")
+
+	data, err := os.ReadFile(a)
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond", string(data))
+
+	data, err = os.ReadFile(b)
+	require.NoError(t, err)
+	assert.Equal(t, "other", string(data))
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "12ms", FormatDuration(12*time.Millisecond))
+	assert.Equal(t, "1.50s", FormatDuration(1500*time.Millisecond))
+}
+
+func TestFailureKind(t *testing.T) {
+	assert.Empty(t, FailureKind(plugin.TestFailureKindNone))
+	assert.Equal(t, "soft", FailureKind(plugin.TestFailureKindSoft))
+	assert.Equal(t, "fatal", FailureKind(plugin.TestFailureKindFatal))
+	assert.Equal(t, "timeout", FailureKind(plugin.TestFailureKindTimeout))
+}
+
+func TestFirstLine(t *testing.T) {
+	assert.Empty(t, FirstLine(nil))
+	assert.Equal(t, "first", FirstLine([]string{"first\nsecond", "third"}))
+}
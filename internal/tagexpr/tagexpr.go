@@ -0,0 +1,209 @@
+// Package tagexpr implements boolean expressions over test tags.
+//
+// Expressions consist of tags combined with "&&", "||" and "!" operators
+// and grouped with parentheses:
+//
+//	integration && !(slow || flaky)
+//
+// "!" binds tighter than "&&", which binds tighter than "||".
+package tagexpr
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Expr is a parsed tags expression.
+type Expr interface {
+	// Match states whether the given tags satisfy the expression.
+	Match(tags []string) bool
+
+	fmt.Stringer
+}
+
+// Parse the given expression.
+func Parse(s string) (Expr, error) {
+	p := parser{tokens: tokenize(s)}
+
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("parse tags expression %q: empty expression", s)
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("parse tags expression %q: %w", s, err)
+	}
+
+	if tok, ok := p.peek(); ok {
+		return nil, fmt.Errorf("parse tags expression %q: unexpected %q", s, tok)
+	}
+
+	return expr, nil
+}
+
+type (
+	tagExpr string
+	notExpr struct{ Expr Expr }
+	andExpr struct{ Left, Right Expr }
+	orExpr  struct{ Left, Right Expr }
+)
+
+func (e tagExpr) Match(tags []string) bool { return slices.Contains(tags, string(e)) }
+func (e notExpr) Match(tags []string) bool { return !e.Expr.Match(tags) }
+func (e andExpr) Match(tags []string) bool { return e.Left.Match(tags) && e.Right.Match(tags) }
+func (e orExpr) Match(tags []string) bool  { return e.Left.Match(tags) || e.Right.Match(tags) }
+
+func (e tagExpr) String() string { return string(e) }
+func (e notExpr) String() string { return "!" + e.Expr.String() }
+func (e andExpr) String() string { return "(" + e.Left.String() + " && " + e.Right.String() + ")" }
+func (e orExpr) String() string  { return "(" + e.Left.String() + " || " + e.Right.String() + ")" }
+
+// isTagRune states whether r may be a part of the tag.
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.:/", r)
+}
+
+// tokenize splits the expression into operators, parentheses and tags.
+//
+// Unknown characters are returned as separate tokens,
+// so that parser reports them.
+func tokenize(s string) []string {
+	var tokens []string
+
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case isTagRune(r):
+			start := i
+
+			for i < len(runes) && isTagRune(runes[i]) {
+				i++
+			}
+
+			tokens = append(tokens, string(runes[start:i]))
+
+		case (r == '&' || r == '|') && i+1 < len(runes) && runes[i+1] == r:
+			tokens = append(tokens, string(runes[i:i+2]))
+			i += 2
+
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (string, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return "", errors.New("unexpected end of expression")
+	}
+
+	p.pos++
+
+	return tok, nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for tok, ok := p.peek(); ok && tok == "||"; tok, ok = p.peek() {
+		p.pos++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orExpr{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for tok, ok := p.peek(); ok && tok == "&&"; tok, ok = p.peek() {
+		p.pos++
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andExpr{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tok == "!":
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpr{Expr: expr}, nil
+
+	case tok == "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		closing, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing != ")" {
+			return nil, fmt.Errorf("unexpected %q, expected \")\"", closing)
+		}
+
+		return expr, nil
+
+	case isTagRune([]rune(tok)[0]):
+		return tagExpr(tok), nil
+
+	default:
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+}
@@ -0,0 +1,79 @@
+package tagexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: "slow", want: "slow"},
+		{expr: "!slow", want: "!slow"},
+		{expr: "integration && !slow", want: "(integration && !slow)"},
+		{expr: "a || b && c", want: "(a || (b && c))"},
+		{expr: "(a || b) && c", want: "((a || b) && c)"},
+		{expr: "!(a || b)", want: "!(a || b)"},
+		{expr: "a && b && c", want: "((a && b) && c)"},
+		{expr: " db:postgres  ||  i18n/ru ", want: "(db:postgres || i18n/ru)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Parse(tt.expr)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, expr.String())
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: "", want: `parse tags expression "": empty expression`},
+		{expr: "a &&", want: `parse tags expression "a &&": unexpected end of expression`},
+		{expr: "a & b", want: `parse tags expression "a & b": unexpected "&"`},
+		{expr: "(a || b", want: `parse tags expression "(a || b": unexpected end of expression`},
+		{expr: "a b", want: `parse tags expression "a b": unexpected "b"`},
+		{expr: "a)", want: `parse tags expression "a)": unexpected ")"`},
+		{expr: "(a b)", want: `parse tags expression "(a b)": unexpected "b", expected ")"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestExpr_Match(t *testing.T) {
+	tests := []struct {
+		expr string
+		tags []string
+		want bool
+	}{
+		{expr: "integration", tags: []string{"integration"}, want: true},
+		{expr: "integration", tags: nil, want: false},
+		{expr: "!slow", tags: nil, want: true},
+		{expr: "integration && !slow", tags: []string{"integration"}, want: true},
+		{expr: "integration && !slow", tags: []string{"integration", "slow"}, want: false},
+		{expr: "a || b", tags: []string{"b"}, want: true},
+		{expr: "!(a || b)", tags: []string{"c"}, want: true},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.expr)
+		require.NoError(t, err)
+
+		assert.Equal(t, tt.want, expr.Match(tt.tags), "%s on %v", tt.expr, tt.tags)
+	}
+}
@@ -0,0 +1,17 @@
+// Package update provides the flag shared by plugins
+// which update expected test results, e.g. golden files and snapshots.
+package update
+
+import "flag"
+
+//nolint:gochecknoglobals // flags can be global
+var enabled = flag.Bool(
+	"testo.update",
+	false,
+	"update golden files and snapshots with the actual values instead of comparing them",
+)
+
+// Enabled states whether expected test results should be updated.
+func Enabled() bool {
+	return *enabled
+}
@@ -0,0 +1,68 @@
+package testo
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/metafates/testo/internal/stack"
+)
+
+// lifetime binds cleanups, temporary directories and context of [T]
+// to something other than its [testing.T], e.g. to the test attempt or to the [ScopeRun].
+type lifetime struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	mu       sync.Mutex
+	cleanups stack.Stack[func()]
+}
+
+func newLifetime(parent context.Context) *lifetime {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	return &lifetime{
+		ctx:      ctx,
+		cancel:   cancel,
+		cleanups: stack.New[func()](),
+	}
+}
+
+// Cleanup registers a function to be called on [lifetime.End].
+func (l *lifetime) Cleanup(f func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cleanups.Push(f)
+}
+
+// TempDir creates a new temporary directory removed on [lifetime.End].
+func (l *lifetime) TempDir() (string, error) {
+	dir, err := os.MkdirTemp("", "testo")
+	if err != nil {
+		return "", err
+	}
+
+	l.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	return dir, nil
+}
+
+// End cancels the context and calls registered cleanups in the reverse order.
+//
+// Each cleanup is run with [probe], so that FailNow would stop only that cleanup.
+func (l *lifetime) End() {
+	l.cancel(context.Canceled)
+
+	for {
+		l.mu.Lock()
+		cleanup, ok := l.cleanups.Pop()
+		l.mu.Unlock()
+
+		if !ok {
+			return
+		}
+
+		probe(cleanup)
+	}
+}
@@ -479,7 +479,7 @@ func (a *Allure) results() []result {
 	for _, test := range a.children {
 		if p, ok := testo.Inspect(test).Test.(plugin.ParametrizedTestInfo); ok {
 			name := Name{
-				Full: removeCaseSuffix(test.Name()),
+				Full: a.Name() + "/" + p.RawBaseName,
 				Base: p.RawBaseName,
 			}
 
@@ -762,18 +762,6 @@ func filenameForAttachment(attachment Attachment) string {
 	return attachment.UUID() + "-attachment" + ext
 }
 
-func removeCaseSuffix(testName string) string {
-	// RealName_case_%d
-	//         ^
-	idx := strings.LastIndex(testName, "_case_")
-
-	if idx < 0 {
-		return testName
-	}
-
-	return testName[:idx]
-}
-
 func testBaseName(testName string) string {
 	segments := strings.Split(testName, "/")
 
@@ -0,0 +1,423 @@
+// Package console provides hierarchical console output of the suite run as a plugin for testo.
+package console
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/eventtree"
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/internal/reportutil"
+	"github.com/metafates/testo/plugin"
+)
+
+//nolint:gochecknoglobals // flags can be global
+var colorFlag = flag.String(
+	"console.color",
+	"auto",
+	`color console output: "always", "never" or "auto" to color it when writing to a terminal`,
+)
+
+// DefaultSlowest is the default number of the slowest tests in the summary.
+const DefaultSlowest = 5
+
+type option func(*reporter)
+
+// WithWriter sets the writer to print the output to.
+//
+// By default, it is [os.Stdout].
+func WithWriter(w io.Writer) plugin.Option {
+	return plugin.Option{
+		Value: option(func(r *reporter) {
+			r.w = w
+		}),
+	}
+}
+
+// WithColor enables or disables colors of the output.
+// It takes precedence over -console.color flag.
+func WithColor(enabled bool) plugin.Option {
+	return plugin.Option{
+		Value: option(func(r *reporter) {
+			r.color = &enabled
+		}),
+	}
+}
+
+// WithSlowest sets the number of the slowest tests in the summary.
+// Zero disables them.
+//
+// By default, it is [DefaultSlowest].
+func WithSlowest(n int) plugin.Option {
+	return plugin.Option{
+		Value: option(func(r *reporter) {
+			r.slowest = n
+		}),
+	}
+}
+
+// Console is a plugin which prints the tree of the suite, its tests,
+// parametrized cases with their params and subtests as they finish,
+// followed by the summary with the slowest and failed tests.
+//
+// Logs of the tests, e.g. by t.Log, are printed indented under their test,
+// so they are not interleaved with logs of the parallel tests.
+type Console struct {
+	*testo.T
+
+	// reporter is shared by all tests of the suite.
+	reporter *reporter
+}
+
+// Init plugin.
+func (c *Console) Init(parent *Console, options ...plugin.Option) {
+	if parent != nil && parent.reporter != nil {
+		c.reporter = parent.reporter
+
+		return
+	}
+
+	c.reporter = &reporter{
+		w:       os.Stdout,
+		slowest: DefaultSlowest,
+		tree:    eventtree.New(),
+	}
+
+	for _, o := range options {
+		if o, ok := o.Value.(option); ok {
+			o(c.reporter)
+		}
+	}
+
+	if c.reporter.color != nil {
+		return
+	}
+
+	color, err := isColored(*colorFlag, c.reporter.w)
+	if err != nil {
+		c.Fatalf("%v", err)
+
+		return
+	}
+
+	c.reporter.color = &color
+}
+
+// Plugin implements [plugin.Plugin].
+func (c *Console) Plugin() plugin.Spec {
+	return plugin.Spec{
+		Observer: c.reporter.observe,
+	}
+}
+
+func isColored(value string, w io.Writer) (bool, error) {
+	switch value {
+	case "always":
+		return true, nil
+
+	case "never":
+		return false, nil
+
+	case "", "auto":
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return false, nil
+		}
+
+		f, ok := w.(*os.File)
+		if !ok {
+			return false, nil
+		}
+
+		stat, err := f.Stat()
+		if err != nil {
+			return false, nil //nolint:nilerr // colors are optional
+		}
+
+		return stat.Mode()&os.ModeCharDevice != 0, nil
+
+	default:
+		return false, fmt.Errorf(
+			`invalid -console.color flag %q: must be "always", "never" or "auto"`,
+			value,
+		)
+	}
+}
+
+// reporter prints the output of the suite.
+type reporter struct {
+	w       io.Writer
+	color   *bool
+	slowest int
+
+	mu   sync.Mutex
+	tree *eventtree.Tree
+
+	// group is the parametrized test whose case was printed last.
+	group string
+}
+
+func (r *reporter) observe(event plugin.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tree.Add(event)
+
+	switch e := event.(type) {
+	case plugin.SuiteStarted:
+		fmt.Fprintln(r.w, r.bold(e.Suite))
+
+	case plugin.Log:
+		// logs outside suite tests, e.g. in BeforeAll hook, are printed at once
+		if r.tree.Suite != nil && e.Test.ID == r.tree.Suite.ID {
+			r.printLines(1, e.Message, r.dim)
+		}
+
+	case plugin.TestFinished:
+		test, ok := r.tree.Test(e.Test.ID)
+
+		if ok && test.Parent == r.tree.Suite {
+			r.printSuiteTest(test)
+		}
+
+	case plugin.SuiteFinished:
+		r.printSummary()
+	}
+}
+
+// printSuiteTest prints the finished suite test with its subtests.
+// Cases of the parametrized test are printed under their test.
+func (r *reporter) printSuiteTest(test *eventtree.Test) {
+	info, ok := test.Info.(plugin.ParametrizedTestInfo)
+
+	// property tests have no cases
+	if !ok || info.Strategy == "" {
+		r.group = ""
+		r.printTest(test, test.RawBaseName(), 1)
+
+		return
+	}
+
+	if r.group != info.RawBaseName {
+		r.group = info.RawBaseName
+
+		fmt.Fprintln(r.w, indent(1)+info.RawBaseName)
+	}
+
+	r.printTest(test, test.CaseName(), 2)
+}
+
+func (r *reporter) printTest(test *eventtree.Test, name string, depth int) {
+	line := indent(depth) + r.glyph(test) + " " + name
+
+	if info, ok := test.Info.(plugin.ParametrizedTestInfo); ok && len(info.Params) > 0 {
+		line += " " + r.dim(formatParams(info.Params))
+	}
+
+	if test.Attempt > 1 && test.Parent == r.tree.Suite {
+		line += r.yellow(fmt.Sprintf(" (attempt %d)", test.Attempt))
+	}
+
+	if (test.IsSkipped || test.IsPending()) && test.Result.Message != "" {
+		line += " - " + test.Result.Message
+	}
+
+	line += " " + r.dim("("+reportutil.FormatDuration(test.Result.Duration)+")")
+
+	fmt.Fprintln(r.w, line)
+
+	for _, msg := range test.Logs {
+		r.printLines(depth+1, msg, r.dim)
+	}
+
+	for _, msg := range test.Failures {
+		r.printLines(depth+1, msg, r.red)
+	}
+
+	for _, child := range test.Children {
+		r.printTest(child, child.RawBaseName(), depth+1)
+	}
+}
+
+func (r *reporter) printLines(depth int, msg string, style func(string) string) {
+	for _, line := range strings.Split(msg, "\n") {
+		fmt.Fprintln(r.w, indent(depth)+style(line))
+	}
+}
+
+func (r *reporter) printSummary() {
+	suite := r.tree.Suite
+
+	var (
+		tests                            = suite.Children
+		passed, failed, skipped, pending int
+	)
+
+	for _, test := range tests {
+		switch {
+		case test.Failed():
+			failed++
+
+		case test.IsPending():
+			pending++
+
+		case test.IsSkipped:
+			skipped++
+
+		default:
+			passed++
+		}
+	}
+
+	counts := []string{r.green(fmt.Sprintf("%d passed", passed))}
+
+	if failed > 0 {
+		counts = append(counts, r.red(fmt.Sprintf("%d failed", failed)))
+	}
+
+	if skipped > 0 {
+		counts = append(counts, r.yellow(fmt.Sprintf("%d skipped", skipped)))
+	}
+
+	if pending > 0 {
+		counts = append(counts, r.yellow(fmt.Sprintf("%d pending", pending)))
+	}
+
+	fmt.Fprintf(
+		r.w,
+		"\n%s: %s %s\n",
+		r.bold(r.tree.SuiteName),
+		strings.Join(counts, ", "),
+		r.dim("("+reportutil.FormatDuration(suite.Result.Duration)+")"),
+	)
+
+	r.printSlowest(tests)
+	r.printFailed(tests)
+}
+
+func (r *reporter) printSlowest(tests []*eventtree.Test) {
+	if r.slowest <= 0 {
+		return
+	}
+
+	var slowest []*eventtree.Test
+
+	for _, test := range tests {
+		if !test.IsSkipped {
+			slowest = append(slowest, test)
+		}
+	}
+
+	if len(slowest) == 0 {
+		return
+	}
+
+	slices.SortStableFunc(slowest, func(a, b *eventtree.Test) int {
+		return int(b.Result.Duration - a.Result.Duration)
+	})
+
+	slowest = slowest[:min(len(slowest), r.slowest)]
+
+	fmt.Fprintln(r.w, "\nSlowest tests:")
+
+	w := tabwriter.NewWriter(r.w, 0, 0, 2, ' ', 0)
+
+	for _, test := range slowest {
+		fmt.Fprintf(w, "%s%s\t%s\n", indent(1), test.BaseName, reportutil.FormatDuration(test.Result.Duration))
+	}
+
+	_ = w.Flush()
+}
+
+func (r *reporter) printFailed(tests []*eventtree.Test) {
+	var failed []*eventtree.Test
+
+	for _, test := range tests {
+		if test.Failed() {
+			failed = append(failed, test)
+		}
+	}
+
+	// failures of the suite itself, e.g. in BeforeAll hook
+	if len(r.tree.Suite.Failures) > 0 {
+		suite := *r.tree.Suite
+
+		suite.BaseName = r.tree.SuiteName
+		failed = append(failed, &suite)
+	}
+
+	if len(failed) == 0 {
+		return
+	}
+
+	fmt.Fprintln(r.w, "\nFailed tests:")
+
+	w := tabwriter.NewWriter(r.w, 0, 0, 2, ' ', 0)
+
+	for _, test := range failed {
+		fmt.Fprintf(w, "%s%s %s\t%s\n", indent(1), r.red(glyphFailed), test.BaseName, reportutil.FirstLine(test.Failures))
+	}
+
+	_ = w.Flush()
+}
+
+const (
+	glyphPassed  = "✓"
+	glyphFailed  = "✗"
+	glyphSkipped = "↷"
+	glyphPending = "○"
+)
+
+func (r *reporter) glyph(test *eventtree.Test) string {
+	switch {
+	case test.Failed():
+		return r.red(glyphFailed)
+
+	case test.IsPending():
+		return r.yellow(glyphPending)
+
+	case test.IsSkipped:
+		return r.yellow(glyphSkipped)
+
+	default:
+		return r.green(glyphPassed)
+	}
+}
+
+func (r *reporter) bold(s string) string   { return r.style("1", s) }
+func (r *reporter) dim(s string) string    { return r.style("2", s) }
+func (r *reporter) red(s string) string    { return r.style("31", s) }
+func (r *reporter) green(s string) string  { return r.style("32", s) }
+func (r *reporter) yellow(s string) string { return r.style("33", s) }
+
+// style wraps s with ANSI escape code if colors are enabled.
+func (r *reporter) style(code, s string) string {
+	if r.color == nil || !*r.color || s == "" {
+		return s
+	}
+
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func indent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+func formatParams(params map[string]any) string {
+	names := maputil.Keys(params)
+	slices.Sort(names)
+
+	pairs := make([]string, 0, len(names))
+
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", name, params[name]))
+	}
+
+	return "[" + strings.Join(pairs, " ") + "]"
+}
@@ -0,0 +1,185 @@
+package console
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/eventtree"
+	"github.com/metafates/testo/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+type T = *struct {
+	*testo.T
+
+	Console
+}
+
+type Suite struct{}
+
+func TestConsole(t *testing.T) {
+	var out strings.Builder
+
+	testo.RunSuite[*Suite, T](t,
+		WithWriter(&out),
+		WithColor(false),
+		WithSlowest(2),
+		testo.WithRetry(testo.RetryPolicy{Retries: 1}),
+	)
+
+	got := regexp.MustCompile(`\d+ms`).ReplaceAllString(out.String(), "Xms")
+
+	assert.Equal(t, `Suite
+  before all
+  ✗ TestFlaky (Xms)
+    first attempt fails
+  ✓ TestFlaky (attempt 2) (Xms)
+    attempt 2
+  ✓ TestPass (Xms)
+    hello
+  ↷ TestSkip - later (Xms)
+  ✓ TestSub (Xms)
+    ✓ first (Xms)
+      in first
+    ✓ second (Xms)
+      in second
+      ✓ nested test (Xms)
+        in nested
+  ○ XTestLater - pending (Xms)
+  TestCases
+    ✓ case_1 [N=1] (Xms)
+    ✓ case_2 [N=2] (Xms)
+
+Suite: 5 passed, 1 skipped, 1 pending (Xms)
+
+Slowest tests:
+  TestPass  Xms
+  TestSub   Xms
+`, got)
+}
+
+func TestReporter_printSummary(t *testing.T) {
+	var out strings.Builder
+
+	r := &reporter{w: &out, slowest: 1, tree: eventtree.New()}
+
+	r.tree.SuiteName = "Suite"
+	r.tree.Suite = &eventtree.Test{
+		Name:     "Suite",
+		Failures: []string{"AfterAll failed"},
+		Result:   plugin.Result{FailureKind: plugin.TestFailureKindSoft, Duration: 2 * time.Second},
+	}
+
+	r.tree.Suite.Children = []*eventtree.Test{
+		{
+			BaseName: "TestFail",
+			Failures: []string{"first\nsecond", "third"},
+			Result: plugin.Result{
+				FailureKind: plugin.TestFailureKindFatal,
+				Duration:    20 * time.Millisecond,
+			},
+		},
+		{
+			BaseName: "TestSlow",
+			Result:   plugin.Result{Duration: 1500 * time.Millisecond},
+		},
+	}
+
+	r.printSummary()
+
+	assert.Equal(t, `
+Suite: 1 passed, 1 failed (2.00s)
+
+Slowest tests:
+  TestSlow  1.50s
+
+Failed tests:
+  ✗ TestFail  first
+  ✗ Suite     AfterAll failed
+`, out.String())
+}
+
+func TestReporter_style(t *testing.T) {
+	color := true
+
+	r := &reporter{color: &color}
+
+	assert.Equal(t, "\x1b[31mfailed\x1b[0m", r.red("failed"))
+	assert.Empty(t, r.red(""))
+
+	color = false
+
+	assert.Equal(t, "failed", r.red("failed"))
+}
+
+func TestIsColored(t *testing.T) {
+	var out strings.Builder
+
+	for value, want := range map[string]bool{
+		"always": true,
+		"never":  false,
+		"auto":   false,
+	} {
+		got, err := isColored(value, &out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, value)
+	}
+
+	_, err := isColored("sometimes", &out)
+
+	assert.Error(t, err)
+}
+
+func (Suite) BeforeAll(t T) {
+	t.Log("before all")
+}
+
+func (Suite) CasesN() []int {
+	return []int{1, 2}
+}
+
+func (Suite) TestPass(t T) {
+	time.Sleep(50 * time.Millisecond)
+
+	t.Log("hello")
+}
+
+func (Suite) TestSub(t T) {
+	time.Sleep(10 * time.Millisecond)
+
+	testo.Run(t, "first", func(t T) {
+		t.Parallel()
+
+		t.Log("in first")
+	})
+
+	testo.Run(t, "second", func(t T) {
+		t.Parallel()
+
+		t.Logf("in %s", "second")
+
+		testo.Run(t, "nested test", func(t T) {
+			t.Log("in nested")
+		})
+	})
+}
+
+func (Suite) TestFlaky(t T) {
+	if attempt := testo.Inspect(t).Attempt; attempt == 1 {
+		t.Error("first attempt fails")
+	} else {
+		t.Logf("attempt %d", attempt)
+	}
+}
+
+func (Suite) TestCases(T, struct{ N int }) {}
+
+func (Suite) TestSkip(t T) {
+	t.Skip("later")
+}
+
+func (Suite) XTestLater(T) {}
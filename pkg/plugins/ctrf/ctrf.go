@@ -0,0 +1,391 @@
+// Package ctrf provides Common Test Report Format JSON reports as a plugin for testo.
+//
+// See https://ctrf.io for the format.
+package ctrf
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/eventtree"
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/internal/reportutil"
+	"github.com/metafates/testo/plugin"
+)
+
+//nolint:gochecknoglobals // flags can be global
+var output = flag.String(
+	"ctrf.output",
+	"ctrf/ctrf-report.json",
+	"path to the file to write CTRF JSON report to",
+)
+
+//nolint:gochecknoglobals // shared by all suites
+var files reportutil.Files[results]
+
+type option func(*CTRF)
+
+// WithOutput sets the path of the file to write the report to.
+// It takes precedence over -ctrf.output flag.
+func WithOutput(path string) plugin.Option {
+	return plugin.Option{
+		Value: option(func(c *CTRF) {
+			c.output = path
+		}),
+	}
+}
+
+// CTRF is a plugin which writes Common Test Report Format JSON report
+// to the file given by -ctrf.output flag.
+//
+// Each suite test and parametrized case is reported as a test
+// with its suite, tags, parameters, retries and source location of the suite method.
+// Subtests are reported as steps of their test.
+//
+// Suites of the package are written to the same report, which is rewritten after each suite.
+type CTRF struct {
+	*testo.T
+
+	output string
+
+	// tree is collected by the suite level plugin only.
+	tree *eventtree.Tree
+}
+
+// Init plugin.
+func (c *CTRF) Init(parent *CTRF, options ...plugin.Option) {
+	if parent != nil && parent.tree != nil {
+		return
+	}
+
+	c.output = *output
+
+	for _, o := range options {
+		if o, ok := o.Value.(option); ok {
+			o(c)
+		}
+	}
+
+	c.tree = eventtree.New()
+}
+
+// Plugin implements [plugin.Plugin].
+func (c *CTRF) Plugin() plugin.Spec {
+	if c.tree == nil {
+		return plugin.Spec{}
+	}
+
+	return plugin.Spec{
+		Observer: c.observe,
+	}
+}
+
+func (c *CTRF) observe(event plugin.Event) {
+	c.tree.Add(event)
+
+	if _, ok := event.(plugin.SuiteFinished); !ok {
+		return
+	}
+
+	if err := write(c.output, testsOf(c.tree)); err != nil {
+		c.Errorf("ctrf: %v", err)
+	}
+}
+
+// write the tests to the report at path, along with the tests written before.
+func write(path string, tests []test) error {
+	return files.Write(path, func(r *results) ([]byte, error) {
+		r.Tool = tool{Name: "testo"}
+		r.add(tests)
+
+		data, err := json.MarshalIndent(report{
+			ReportFormat: "CTRF",
+			SpecVersion:  "0.0.0",
+			Results:      r,
+		}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(data, '\n'), nil
+	})
+}
+
+func (r *results) add(tests []test) {
+	for _, t := range tests {
+		r.Summary.Tests++
+
+		switch t.Status {
+		case statusPassed:
+			r.Summary.Passed++
+
+		case statusFailed:
+			r.Summary.Failed++
+
+		case statusSkipped:
+			r.Summary.Skipped++
+
+		case statusPending:
+			r.Summary.Pending++
+
+		default:
+			r.Summary.Other++
+		}
+
+		if r.Summary.Start == 0 || (t.Start != 0 && t.Start < r.Summary.Start) {
+			r.Summary.Start = t.Start
+		}
+
+		r.Summary.Stop = max(r.Summary.Stop, t.Stop)
+	}
+
+	r.Tests = append(r.Tests, tests...)
+}
+
+// testsOf returns the tests of the suite in the report format.
+func testsOf(tree *eventtree.Tree) []test {
+	tests := make([]test, 0, len(tree.Suite.Children))
+
+	for _, t := range tree.Suite.Children {
+		tests = append(tests, newTest(tree, t))
+	}
+
+	// failures of the suite itself, e.g. in BeforeAll hook
+	if len(tree.Suite.Failures) > 0 {
+		t := newTest(tree, tree.Suite)
+
+		t.Name = tree.SuiteName
+
+		tests = append(tests, t)
+	}
+
+	return tests
+}
+
+func newTest(tree *eventtree.Tree, t *eventtree.Test) test {
+	result := test{
+		Name:     t.BaseName,
+		Status:   statusOf(t),
+		Duration: t.Result.Duration.Milliseconds(),
+		Start:    milliseconds(t.Started),
+		Stop:     milliseconds(t.Finished),
+		Suite:    tree.SuiteName,
+		Message:  strings.Join(t.Failures, "\n"),
+		Stdout:   t.Logs,
+		Retries:  len(t.Retries),
+		Steps:    stepsOf(t.Children, t.Name+"/"),
+		Extra: extra{
+			Attempt:     t.Attempt,
+			FailureKind: reportutil.FailureKind(t.Result.FailureKind),
+		},
+	}
+
+	if result.Message == "" && result.Status != statusPassed {
+		result.Message = t.Result.Message
+	}
+
+	result.Flaky = result.Retries > 0 && result.Status == statusPassed
+
+	if t.Failed() {
+		result.RawStatus = result.Extra.FailureKind
+	}
+
+	if t.Result.Panic != nil {
+		result.RawStatus = "panic"
+		result.Trace = t.Result.Panic.Trace
+		result.Extra.Panic = fmt.Sprint(t.Result.Panic.Value)
+	}
+
+	var method string
+
+	switch info := t.Info.(type) {
+	case plugin.RegularTestInfo:
+		method = info.RawBaseName
+		result.Tags = info.Tags
+		result.Extra.Level = info.Level
+		result.Extra.Pending = info.Pending
+
+	case plugin.ParametrizedTestInfo:
+		method = info.RawBaseName
+		result.Tags = info.Tags
+		result.Parameters = parametersOf(info.Params)
+		result.Extra.Level = 1
+		result.Extra.CaseName = info.CaseName
+		result.Extra.Strategy = info.Strategy
+	}
+
+	if method != "" {
+		result.FilePath, result.Line = sourceOf(tree.SuiteType, method)
+	}
+
+	return result
+}
+
+// stepsOf returns the subtests as steps, including the nested ones.
+// Names of the steps are relative to the test.
+func stepsOf(tests []*eventtree.Test, prefix string) []step {
+	var steps []step
+
+	for _, t := range tests {
+		steps = append(steps, step{
+			Name:   strings.TrimPrefix(t.Name, prefix),
+			Status: statusOf(t),
+		})
+
+		steps = append(steps, stepsOf(t.Children, prefix)...)
+	}
+
+	return steps
+}
+
+func statusOf(t *eventtree.Test) string {
+	switch {
+	case t.Failed():
+		return statusFailed
+
+	case t.IsPending():
+		return statusPending
+
+	case t.IsSkipped:
+		return statusSkipped
+
+	case t.IsFinished:
+		return statusPassed
+
+	default:
+		return statusOther
+	}
+}
+
+func parametersOf(params map[string]any) map[string]string {
+	if len(params) == 0 {
+		return nil
+	}
+
+	parameters := make(map[string]string, len(params))
+
+	for _, name := range maputil.Keys(params) {
+		parameters[name] = fmt.Sprint(params[name])
+	}
+
+	return parameters
+}
+
+// sourceOf returns the file and line where the suite method is declared.
+// It returns zero values if the method is not found.
+func sourceOf(suite reflect.Type, name string) (string, int) {
+	if suite == nil {
+		return "", 0
+	}
+
+	types := []reflect.Type{suite}
+
+	// methods with value receivers of the pointer type are autogenerated wrappers,
+	// so they are looked up in the element type first.
+	if suite.Kind() == reflect.Pointer {
+		types = slices.Insert(types, 0, suite.Elem())
+	}
+
+	for _, typ := range types {
+		method, ok := typ.MethodByName(name)
+		if !ok {
+			continue
+		}
+
+		fn := runtime.FuncForPC(method.Func.Pointer())
+		if fn == nil {
+			return "", 0
+		}
+
+		return fn.FileLine(fn.Entry())
+	}
+
+	return "", 0
+}
+
+func milliseconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixMilli()
+}
+
+const (
+	statusPassed  = "passed"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+	statusPending = "pending"
+	statusOther   = "other"
+)
+
+type report struct {
+	ReportFormat string   `json:"reportFormat"`
+	SpecVersion  string   `json:"specVersion"`
+	Results      *results `json:"results"`
+}
+
+type results struct {
+	Tool    tool    `json:"tool"`
+	Summary summary `json:"summary"`
+	Tests   []test  `json:"tests"`
+}
+
+type tool struct {
+	Name string `json:"name"`
+}
+
+type summary struct {
+	Tests   int   `json:"tests"`
+	Passed  int   `json:"passed"`
+	Failed  int   `json:"failed"`
+	Pending int   `json:"pending"`
+	Skipped int   `json:"skipped"`
+	Other   int   `json:"other"`
+	Start   int64 `json:"start"`
+	Stop    int64 `json:"stop"`
+}
+
+type test struct {
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	Duration   int64             `json:"duration"`
+	Start      int64             `json:"start,omitempty"`
+	Stop       int64             `json:"stop,omitempty"`
+	Suite      string            `json:"suite,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Trace      string            `json:"trace,omitempty"`
+	RawStatus  string            `json:"rawStatus,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	FilePath   string            `json:"filePath,omitempty"`
+	Line       int               `json:"line,omitempty"`
+	Retries    int               `json:"retries"`
+	Flaky      bool              `json:"flaky"`
+	Stdout     []string          `json:"stdout,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Steps      []step            `json:"steps,omitempty"`
+	Extra      extra             `json:"extra"`
+}
+
+type step struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// extra is testo specific information about the test, see [testo.Inspect].
+type extra struct {
+	Attempt     int    `json:"attempt"`
+	Level       int    `json:"level"`
+	Pending     bool   `json:"pending,omitempty"`
+	CaseName    string `json:"caseName,omitempty"`
+	Strategy    string `json:"strategy,omitempty"`
+	FailureKind string `json:"failureKind,omitempty"`
+	Panic       string `json:"panic,omitempty"`
+}
@@ -0,0 +1,183 @@
+package ctrf
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/metafates/testo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type T = *struct {
+	*testo.T
+
+	CTRF
+}
+
+type (
+	Suite        struct{}
+	PointerSuite struct{}
+)
+
+func TestCTRF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "report.json")
+
+	testo.RunSuite[*Suite, T](t,
+		WithOutput(path),
+		testo.WithRetry(testo.RetryPolicy{Retries: 1}),
+	)
+	testo.RunSuite[*PointerSuite, T](t, WithOutput(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var r report
+
+	require.NoError(t, json.Unmarshal(data, &r))
+
+	assert.Equal(t, "CTRF", r.ReportFormat)
+	assert.Equal(t, "testo", r.Results.Tool.Name)
+
+	s := r.Results.Summary
+
+	assert.Equal(t, 7, s.Tests)
+	assert.Equal(t, 5, s.Passed)
+	assert.Equal(t, 1, s.Skipped)
+	assert.Equal(t, 1, s.Pending)
+	assert.Zero(t, s.Failed)
+	assert.Positive(t, s.Start)
+	assert.GreaterOrEqual(t, s.Stop, s.Start)
+
+	tests := make(map[string]test)
+
+	for _, test := range r.Results.Tests {
+		tests[test.Name] = test
+	}
+
+	pass := tests["TestPass"]
+
+	assert.Equal(t, statusPassed, pass.Status)
+	assert.Equal(t, "Suite", pass.Suite)
+	assert.Equal(t, []string{"fast"}, pass.Tags)
+	assert.Equal(t, []string{"hello"}, pass.Stdout)
+	assert.Equal(t, extra{Attempt: 1, Level: 1}, pass.Extra)
+	assert.False(t, pass.Flaky)
+	assertSource(t, "func (Suite) TestPass(", pass)
+
+	flaky := tests["TestFlaky"]
+
+	assert.Equal(t, statusPassed, flaky.Status)
+	assert.Equal(t, 1, flaky.Retries)
+	assert.True(t, flaky.Flaky)
+	assert.Equal(t, 2, flaky.Extra.Attempt)
+	assert.Equal(t, []step{
+		{Name: "sub", Status: statusPassed},
+		{Name: "sub/nested", Status: statusPassed},
+	}, flaky.Steps)
+
+	skip := tests["TestSkip"]
+
+	assert.Equal(t, statusSkipped, skip.Status)
+	assert.Equal(t, "later", skip.Message)
+
+	assert.Equal(t, statusPending, tests["XTestLater"].Status)
+	assert.True(t, tests["XTestLater"].Extra.Pending)
+
+	c := tests["TestCases_case_2"]
+
+	assert.Equal(t, map[string]string{"N": "2"}, c.Parameters)
+	assert.Equal(t, "cartesian", c.Extra.Strategy)
+	assertSource(t, "func (Suite) TestCases(", c)
+
+	other := tests["TestPointer"]
+
+	assert.Equal(t, "PointerSuite", other.Suite)
+	assertSource(t, "func (*PointerSuite) TestPointer(", other)
+}
+
+func TestSourceOf(t *testing.T) {
+	line := lineOf(t, "func (Suite) TestPass(")
+
+	file, got := sourceOf(reflect.TypeFor[*Suite](), "TestPass")
+
+	assert.True(t, strings.HasSuffix(file, testFile))
+	assert.Equal(t, line, got)
+
+	file, got = sourceOf(reflect.TypeFor[*Suite](), "TestMissing")
+
+	assert.Empty(t, file)
+	assert.Zero(t, got)
+
+	file, got = sourceOf(nil, "TestPass")
+
+	assert.Empty(t, file)
+	assert.Zero(t, got)
+}
+
+func assertSource(t *testing.T, decl string, got test) {
+	t.Helper()
+
+	line := lineOf(t, decl)
+
+	assert.True(t, strings.HasSuffix(got.FilePath, testFile), got.FilePath)
+	assert.Equal(t, line, got.Line)
+}
+
+const testFile = "ctrf_test.go"
+
+// lineOf returns the line of the declaration in this file.
+func lineOf(t *testing.T, decl string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, decl) {
+			return i + 1
+		}
+	}
+
+	t.Fatalf("declaration %q not found", decl)
+
+	return 0
+}
+
+func (Suite) Tags() map[string][]string {
+	return map[string][]string{
+		"TestPass": {"fast"},
+	}
+}
+
+func (Suite) CasesN() []int {
+	return []int{1, 2}
+}
+
+func (Suite) TestPass(t T) {
+	t.Log("hello")
+}
+
+func (Suite) TestFlaky(t T) {
+	testo.Run(t, "sub", func(t T) {
+		testo.Run(t, "nested", func(T) {})
+	})
+
+	if testo.Inspect(t).Attempt == 1 {
+		t.Error("first attempt fails")
+	}
+}
+
+func (Suite) TestSkip(t T) {
+	t.Skip("later")
+}
+
+func (Suite) XTestLater(T) {}
+
+func (Suite) TestCases(T, struct{ N int }) {}
+
+func (*PointerSuite) TestPointer(T) {}
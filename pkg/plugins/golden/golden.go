@@ -0,0 +1,267 @@
+// Package golden provides golden files as a plugin for testo.
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/update"
+	"github.com/metafates/testo/plugin"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Files is a golden files plugin.
+//
+// Golden files of the test are stored under testdata/<Suite>/<Test> directory,
+// where each parametrized case and subtest have their own directory.
+type Files struct {
+	*testo.T
+
+	// dir is the directory of golden files of this test.
+	dir string
+
+	// used golden files of the suite.
+	used *usedFiles
+}
+
+// usedFiles tracks golden files of the suite used by the tests which ran.
+type usedFiles struct {
+	mu    sync.Mutex
+	paths map[string]struct{}
+
+	// dirs of the tests which ran.
+	// Tests may be filtered out, e.g. by -run flag,
+	// so only files in these directories are checked for being unused.
+	dirs map[string]struct{}
+}
+
+func (u *usedFiles) add(path string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.paths[path] = struct{}{}
+}
+
+func (u *usedFiles) ran(dir string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.dirs[dir] = struct{}{}
+}
+
+// isUnused states whether the golden file belongs to the test which ran, but was not used by it.
+func (u *usedFiles) isUnused(path string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	_, isUsed := u.paths[path]
+	_, hasRun := u.dirs[filepath.Dir(path)]
+
+	return hasRun && !isUsed
+}
+
+// Init plugin.
+func (f *Files) Init(parent *Files, _ ...plugin.Option) {
+	if parent == nil || parent.used == nil {
+		f.dir = filepath.Join("testdata", sanitize(f.SuiteName()))
+		f.used = &usedFiles{
+			paths: make(map[string]struct{}),
+			dirs:  make(map[string]struct{}),
+		}
+	} else {
+		f.used = parent.used
+		f.dir = filepath.Join(parent.dir, f.testDir())
+	}
+
+	// T is initialized only for the tests which run
+	f.used.ran(f.dir)
+}
+
+// Plugin implements [plugin.Plugin].
+func (f *Files) Plugin() plugin.Spec {
+	return plugin.Spec{
+		Hooks: plugin.Hooks{
+			AfterAll: plugin.Hook{Func: f.afterAll},
+		},
+	}
+}
+
+// Golden compares got with the golden file of the given name.
+// Test fails with the unified diff if they are not equal.
+//
+// Golden file is stored at testdata/<Suite>/<Test>/<name>.golden.
+// Run tests with -testo.update flag to create or update it with got.
+//
+// Got is written as is if it is []byte or string, and as indented JSON otherwise.
+func (f *Files) Golden(name string, got any) {
+	f.Helper()
+
+	data, err := marshal(got)
+	if err != nil {
+		f.Fatalf("golden: marshal %s: %v", name, err)
+
+		return
+	}
+
+	file := filepath.Join(f.dir, sanitize(name)+".golden")
+
+	f.used.add(file)
+
+	if update.Enabled() {
+		if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+			f.Fatalf("golden: %v", err)
+
+			return
+		}
+
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			f.Fatalf("golden: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		f.Errorf("golden: file %s does not exist, run with -testo.update flag to create it", file)
+
+		return
+	}
+
+	if err != nil {
+		f.Fatalf("golden: %v", err)
+
+		return
+	}
+
+	if !bytes.Equal(want, data) {
+		f.Errorf("golden: %s does not match, run with -testo.update flag to update it\n%s", file, diff(file, want, data))
+	}
+}
+
+// testDir returns the directory of this test relative to its parent.
+func (f *Files) testDir() string {
+	switch info := testo.Inspect(f).Test.(type) {
+	case plugin.ParametrizedTestInfo:
+		caseName := info.CaseName
+		if caseName == "" {
+			// unnamed cases are only distinguished by their number, e.g. TestFoo_case_1
+			caseName = strings.TrimPrefix(path.Base(f.Name()), info.RawBaseName+"_")
+		}
+
+		segments := []string{sanitize(info.RawBaseName)}
+
+		for _, s := range strings.Split(caseName, "/") {
+			segments = append(segments, sanitize(s))
+		}
+
+		return filepath.Join(segments...)
+
+	case plugin.RegularTestInfo:
+		return sanitize(info.RawBaseName)
+
+	default:
+		return ""
+	}
+}
+
+// afterAll reports golden files of the suite which were not used by the tests which ran.
+func (f *Files) afterAll() {
+	unused := f.unused()
+
+	if len(unused) == 0 {
+		return
+	}
+
+	f.Logf(
+		"golden: unused files, remove them if they are no longer needed:\n%s",
+		strings.Join(unused, "\n"),
+	)
+}
+
+// unused returns sorted golden files of the suite which were not used by the tests which ran.
+func (f *Files) unused() []string {
+	var unused []string
+
+	_ = filepath.WalkDir(f.dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // missing directory has no unused files
+		}
+
+		if !d.IsDir() && filepath.Ext(file) == ".golden" && f.used.isUnused(file) {
+			unused = append(unused, file)
+		}
+
+		return nil
+	})
+
+	slices.Sort(unused)
+
+	return unused
+}
+
+func marshal(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+
+	case string:
+		return []byte(v), nil
+
+	default:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(data, '\n'), nil
+	}
+}
+
+// diff returns unified diff of the golden file and the actual value.
+func diff(file string, want, got []byte) string {
+	d, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(want),
+		B:        splitLines(got),
+		FromFile: file,
+		ToFile:   "got",
+		Context:  3,
+	})
+
+	return d
+}
+
+// splitLines splits data into lines, keeping line endings.
+//
+// Unlike [difflib.SplitLines], it does not add an empty line
+// after the trailing line ending.
+func splitLines(data []byte) []string {
+	lines := strings.SplitAfter(string(data), "\n")
+
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// sanitize returns name which is safe to use as a path segment.
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.", r) {
+			return r
+		}
+
+		return '_'
+	}, name)
+}
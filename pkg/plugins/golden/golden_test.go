@@ -0,0 +1,124 @@
+package golden
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/metafates/testo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type T = *struct {
+	*testo.T
+
+	Files
+}
+
+type Suite struct{}
+
+func TestGolden(t *testing.T) {
+	// TODO: use t.Chdir() when go is updated
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	require.NoError(t, flag.Set("testo.update", "true"))
+
+	testo.RunSuite[*Suite, T](t)
+
+	require.NoError(t, flag.Set("testo.update", "false"))
+
+	for file, want := range map[string]string{
+		"testdata/Suite/TestFoo/greeting.golden":         "hello",
+		"testdata/Suite/TestFoo/sub_test/nested.golden":  "nested",
+		"testdata/Suite/TestParams/one/value.golden":     "{\n  \"N\": 1\n}\n",
+		"testdata/Suite/TestParams/two/value.golden":     "{\n  \"N\": 2\n}\n",
+		"testdata/Suite/TestUnnamed/case_1/value.golden": "a",
+		"testdata/Suite/TestUnnamed/case_2/value.golden": "b",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+
+		assert.Equal(t, want, string(got), file)
+	}
+
+	// now golden files match
+	testo.RunSuite[*Suite, T](t)
+}
+
+func TestFiles_unused(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, file := range []string{
+		"TestFoo/used.golden",
+		"TestFoo/unused.golden",
+		"TestFoo/sub/unused.golden",
+		"TestFiltered/unused.golden",
+	} {
+		path := filepath.Join(dir, file)
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+
+	f := Files{
+		dir: dir,
+		used: &usedFiles{
+			paths: map[string]struct{}{filepath.Join(dir, "TestFoo/used.golden"): {}},
+			dirs: map[string]struct{}{
+				dir:                               {},
+				filepath.Join(dir, "TestFoo"):     {},
+				filepath.Join(dir, "TestFoo/sub"): {},
+			},
+		},
+	}
+
+	// TestFiltered did not run, e.g. it was filtered out by -run flag
+	assert.Equal(t, []string{
+		filepath.Join(dir, "TestFoo/sub/unused.golden"),
+		filepath.Join(dir, "TestFoo/unused.golden"),
+	}, f.unused())
+}
+
+func (Suite) CasesN() map[string]int {
+	return map[string]int{"one": 1, "two": 2}
+}
+
+func (Suite) CasesS() []string {
+	return []string{"a", "b"}
+}
+
+func (Suite) TestFoo(t T) {
+	t.Golden("greeting", "hello")
+
+	testo.Run(t, "sub test", func(t T) {
+		t.Golden("nested", []byte("nested"))
+	})
+}
+
+func (Suite) TestParams(t T, params struct{ N int }) {
+	t.Golden("value", params)
+}
+
+func (Suite) TestUnnamed(t T, params struct{ S string }) {
+	t.Golden("value", params.S)
+}
+
+func TestDiff(t *testing.T) {
+	got := diff("testdata/foo.golden", []byte("a\nb\nc\n"), []byte("a\nB\nc\n"))
+
+	assert.Equal(t, `--- testdata/foo.golden
++++ got
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+`, got)
+}
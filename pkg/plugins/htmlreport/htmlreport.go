@@ -0,0 +1,482 @@
+// Package htmlreport provides self-contained HTML reports as a plugin for testo.
+package htmlreport
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/eventtree"
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/internal/reportutil"
+	"github.com/metafates/testo/plugin"
+)
+
+//nolint:gochecknoglobals // flags can be global
+var output = flag.String(
+	"htmlreport.output",
+	"testo-report.html",
+	"path to the file to write HTML report to",
+)
+
+//nolint:gochecknoglobals // shared by all suites
+var files reportutil.Files[[]suiteView]
+
+//go:embed report.gohtml
+var reportTemplate string
+
+//nolint:gochecknoglobals // parsed once
+var tmpl = template.Must(template.New("report").Parse(reportTemplate))
+
+type option func(*Report)
+
+// WithOutput sets the path of the file to write the report to.
+// It takes precedence over -htmlreport.output flag.
+func WithOutput(path string) plugin.Option {
+	return plugin.Option{
+		Value: option(func(r *Report) {
+			r.output = path
+		}),
+	}
+}
+
+// Report is a plugin which writes a self-contained HTML report
+// to the file given by -htmlreport.output flag in the AfterAll hook of the suite.
+//
+// The report is a single file with embedded styles and scripts,
+// so it can be browsed without network access, e.g. straight from the CI artifacts.
+// It shows the tree of the suite tests and their subtests as steps,
+// with logs, failures and attachments, and the cases of parametrized tests as tables.
+//
+// Suites of the package are written to the same report, which is rewritten after each suite.
+type Report struct {
+	*testo.T
+
+	output string
+
+	// collector is shared by all tests of the suite.
+	collector *collector
+}
+
+// Init plugin.
+func (r *Report) Init(parent *Report, options ...plugin.Option) {
+	if parent != nil && parent.collector != nil {
+		r.output = parent.output
+		r.collector = parent.collector
+
+		return
+	}
+
+	r.output = *output
+
+	for _, o := range options {
+		if o, ok := o.Value.(option); ok {
+			o(r)
+		}
+	}
+
+	r.collector = &collector{
+		tree:        eventtree.New(),
+		attachments: make(map[attemptKey][]attachment),
+	}
+}
+
+// Plugin implements [plugin.Plugin].
+func (r *Report) Plugin() plugin.Spec {
+	return plugin.Spec{
+		Hooks: plugin.Hooks{
+			AfterAll: plugin.Hook{
+				Priority: plugin.TryLast,
+				Func:     r.afterAll,
+			},
+		},
+		Observer: r.collector.observe,
+	}
+}
+
+// Attach data to the current test, e.g. a screenshot or a response body.
+// It is inlined into the report as data URI.
+//
+// Media type, e.g. "image/png", defines how the attachment is shown.
+// It is detected from the data if empty.
+func (r *Report) Attach(name string, data []byte, mediaType string) {
+	if mediaType == "" {
+		mediaType = http.DetectContentType(data)
+	}
+
+	if _, _, err := mime.ParseMediaType(mediaType); err != nil {
+		mediaType = "application/octet-stream"
+	}
+
+	r.collector.attach(
+		attemptKey{name: r.Name(), attempt: testo.Inspect(r.T).Attempt},
+		attachment{name: name, mediaType: mediaType, data: data},
+	)
+}
+
+func (r *Report) afterAll() {
+	if err := write(r.output, r.collector.suite()); err != nil {
+		r.Errorf("htmlreport: %v", err)
+	}
+}
+
+// write the suite to the report at path, along with the suites written before.
+func write(path string, suite suiteView) error {
+	return files.Write(path, func(suites *[]suiteView) ([]byte, error) {
+		*suites = append(*suites, suite)
+
+		var buf bytes.Buffer
+
+		if err := tmpl.Execute(&buf, reportView{
+			Generated: time.Now().Format(time.RFC1123),
+			Suites:    *suites,
+		}); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	})
+}
+
+// attemptKey identifies the attempt of the test.
+type attemptKey struct {
+	name    string
+	attempt int
+}
+
+type attachment struct {
+	name      string
+	mediaType string
+	data      []byte
+}
+
+// collector collects the tests of the suite and their attachments.
+type collector struct {
+	mu sync.Mutex
+
+	tree        *eventtree.Tree
+	attachments map[attemptKey][]attachment
+}
+
+func (c *collector) observe(event plugin.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tree.Add(event)
+}
+
+func (c *collector) attach(key attemptKey, a attachment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.attachments[key] = append(c.attachments[key], a)
+}
+
+// suite returns the view of the suite run so far.
+func (c *collector) suite() suiteView {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	suite := c.tree.Suite
+
+	view := suiteView{
+		Name:        c.tree.SuiteName,
+		Status:      statusPassed,
+		Duration:    reportutil.FormatDuration(time.Since(suite.Started)),
+		Logs:        suite.Logs,
+		Failures:    suite.Failures,
+		Attachments: c.attachmentsOf(suite),
+	}
+
+	groups := make(map[string]*groupView)
+
+	for _, t := range suite.Children {
+		info, ok := t.Info.(plugin.ParametrizedTestInfo)
+
+		// property tests have no cases
+		if !ok || info.Strategy == "" {
+			test := c.testOf(t, t.RawBaseName())
+
+			view.Counts.add(test.Status)
+			view.Entries = append(view.Entries, entryView{Test: &test})
+
+			continue
+		}
+
+		test := c.testOf(t, t.CaseName())
+
+		view.Counts.add(test.Status)
+
+		group, ok := groups[info.RawBaseName]
+		if !ok {
+			group = &groupView{Name: info.RawBaseName, Status: statusPassed}
+			groups[info.RawBaseName] = group
+
+			view.Entries = append(view.Entries, entryView{Group: group})
+		}
+
+		group.add(caseView{Name: t.CaseName(), Test: test}, info.Params)
+	}
+
+	for _, group := range groups {
+		group.fillValues()
+	}
+
+	if view.Counts.Failed > 0 || len(suite.Failures) > 0 {
+		view.Status = statusFailed
+	}
+
+	return view
+}
+
+func (c *collector) testOf(t *eventtree.Test, name string) testView {
+	test := testView{
+		Name:        name,
+		Status:      statusOf(t),
+		Duration:    reportutil.FormatDuration(t.Result.Duration),
+		Attempt:     t.Attempt,
+		Logs:        t.Logs,
+		Failures:    t.Failures,
+		Attachments: c.attachmentsOf(t),
+	}
+
+	if test.Status == statusSkipped || test.Status == statusPending {
+		test.Message = t.Result.Message
+	}
+
+	switch info := t.Info.(type) {
+	case plugin.RegularTestInfo:
+		test.Tags = info.Tags
+
+	case plugin.ParametrizedTestInfo:
+		test.Tags = info.Tags
+
+		// property tests have no cases, so their params are shown with the test
+		if info.Strategy == "" {
+			test.Params = paramsOf(info.Params)
+		}
+	}
+
+	if t.Result.Panic != nil {
+		test.Panic = fmt.Sprint(t.Result.Panic.Value)
+		test.Trace = t.Result.Panic.Trace
+	}
+
+	for _, child := range t.Children {
+		test.Steps = append(test.Steps, c.testOf(child, child.RawBaseName()))
+	}
+
+	for _, retry := range t.Retries {
+		test.Retries = append(test.Retries, c.testOf(retry, fmt.Sprintf("Attempt %d", retry.Attempt)))
+	}
+
+	return test
+}
+
+func (c *collector) attachmentsOf(t *eventtree.Test) []attachmentView {
+	attachments := c.attachments[attemptKey{name: t.Name, attempt: t.Attempt}]
+
+	views := make([]attachmentView, 0, len(attachments))
+
+	for _, a := range attachments {
+		view := attachmentView{
+			Name:      a.name,
+			MediaType: a.mediaType,
+			//nolint:gosec // data URI is built from base64 encoded data
+			URI: template.URL(
+				"data:" + strings.ReplaceAll(a.mediaType, " ", "") +
+					";base64," + base64.StdEncoding.EncodeToString(a.data),
+			),
+		}
+
+		switch {
+		case strings.HasPrefix(a.mediaType, "image/"):
+			view.IsImage = true
+
+		case isText(a.mediaType):
+			view.Text = string(a.data)
+			view.IsText = true
+		}
+
+		views = append(views, view)
+	}
+
+	return views
+}
+
+func isText(mediaType string) bool {
+	mediaType, _, _ = mime.ParseMediaType(mediaType)
+
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" ||
+		mediaType == "application/yaml" ||
+		mediaType == "application/xml"
+}
+
+func statusOf(t *eventtree.Test) string {
+	switch {
+	case t.Failed():
+		return statusFailed
+
+	case t.IsPending():
+		return statusPending
+
+	case t.IsSkipped:
+		return statusSkipped
+
+	default:
+		return statusPassed
+	}
+}
+
+func paramsOf(params map[string]any) []paramView {
+	names := maputil.Keys(params)
+	slices.Sort(names)
+
+	views := make([]paramView, 0, len(names))
+
+	for _, name := range names {
+		views = append(views, paramView{Name: name, Value: fmt.Sprint(params[name])})
+	}
+
+	return views
+}
+
+const (
+	statusPassed  = "passed"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+	statusPending = "pending"
+)
+
+type reportView struct {
+	Generated string
+	Suites    []suiteView
+}
+
+type suiteView struct {
+	Name        string
+	Status      string
+	Duration    string
+	Counts      counts
+	Logs        []string
+	Failures    []string
+	Attachments []attachmentView
+	Entries     []entryView
+}
+
+type counts struct {
+	Passed, Failed, Skipped, Pending int
+}
+
+func (c *counts) add(status string) {
+	switch status {
+	case statusFailed:
+		c.Failed++
+
+	case statusSkipped:
+		c.Skipped++
+
+	case statusPending:
+		c.Pending++
+
+	default:
+		c.Passed++
+	}
+}
+
+// entryView is either a test or a group of parametrized test cases.
+type entryView struct {
+	Test  *testView
+	Group *groupView
+}
+
+type groupView struct {
+	Name   string
+	Status string
+
+	// Params are the names of the params of the cases.
+	Params []string
+	Cases  []caseView
+
+	// params of the cases, in order.
+	params []map[string]any
+}
+
+type caseView struct {
+	Name   string
+	Values []string
+	Test   testView
+}
+
+func (g *groupView) add(c caseView, params map[string]any) {
+	g.Cases = append(g.Cases, c)
+	g.params = append(g.params, params)
+
+	for name := range params {
+		if !slices.Contains(g.Params, name) {
+			g.Params = append(g.Params, name)
+		}
+	}
+
+	if c.Test.Status == statusFailed {
+		g.Status = statusFailed
+	}
+}
+
+// fillValues fills the values of the cases in the order of the params.
+func (g *groupView) fillValues() {
+	slices.Sort(g.Params)
+
+	for i, params := range g.params {
+		values := make([]string, len(g.Params))
+
+		for j, name := range g.Params {
+			if value, ok := params[name]; ok {
+				values[j] = fmt.Sprint(value)
+			}
+		}
+
+		g.Cases[i].Values = values
+	}
+}
+
+type testView struct {
+	Name        string
+	Status      string
+	Duration    string
+	Attempt     int
+	Message     string
+	Tags        []string
+	Params      []paramView
+	Logs        []string
+	Failures    []string
+	Panic       string
+	Trace       string
+	Attachments []attachmentView
+	Steps       []testView
+	Retries     []testView
+}
+
+type paramView struct {
+	Name, Value string
+}
+
+type attachmentView struct {
+	Name      string
+	MediaType string
+	URI       template.URL
+	IsImage   bool
+	IsText    bool
+	Text      string
+}
@@ -0,0 +1,146 @@
+package htmlreport
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/metafates/testo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type T = *struct {
+	*testo.T
+
+	Report
+}
+
+type (
+	Suite      struct{}
+	OtherSuite struct{}
+)
+
+// png is 1x1 transparent PNG image.
+const png = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII="
+
+func TestReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "report.html")
+
+	testo.RunSuite[*Suite, T](t,
+		WithOutput(path),
+		testo.WithRetry(testo.RetryPolicy{Retries: 1}),
+	)
+	testo.RunSuite[*OtherSuite, T](t, WithOutput(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	report := string(data)
+
+	// self-contained
+	assert.NotRegexp(t, regexp.MustCompile(`(src|href)="(https?:)?//`), report)
+	assert.NotContains(t, report, "<link")
+
+	for _, want := range []string{
+		`<span class="name">Suite</span>`,
+		`<span class="name">OtherSuite</span>`,
+		`<details class="entry test passed" data-status="passed">`,
+		`<span class="name">TestPass</span>`,
+		"hello from test",
+		"before all",
+		`<span class="name">nested step</span>`,
+		"in step",
+		"first attempt fails",
+		`<span class="tag">attempt 2</span>`,
+		"Previous attempts",
+		`<span class="name">Attempt 1</span>`,
+		`<span class="message">- later</span>`,
+		`<details class="entry test pending" data-status="pending">`,
+		`<details class="entry group passed">`,
+		"<th>Name</th>",
+		"<th>Size</th>",
+		"<td>big</td>",
+		"<td>10</td>",
+		`<span class="name">case_4</span>`,
+		`href="data:text/plain;charset=utf-8;base64,` + base64.StdEncoding.EncodeToString([]byte("body")),
+		`<pre>body</pre>`,
+		`src="data:image/png;base64,` + png + `"`,
+		"Passed: 7",
+		"Skipped: 1",
+		"Pending: 1",
+	} {
+		assert.Contains(t, report, want)
+	}
+}
+
+func TestGroupView_fillValues(t *testing.T) {
+	var g groupView
+
+	g.add(caseView{Name: "first"}, map[string]any{"B": 1, "A": "x"})
+	g.add(caseView{Name: "second", Test: testView{Status: statusFailed}}, map[string]any{"C": true})
+
+	g.fillValues()
+
+	assert.Equal(t, []string{"A", "B", "C"}, g.Params)
+	assert.Equal(t, []string{"x", "1", ""}, g.Cases[0].Values)
+	assert.Equal(t, []string{"", "", "true"}, g.Cases[1].Values)
+	assert.Equal(t, statusFailed, g.Status)
+}
+
+func TestIsText(t *testing.T) {
+	assert.True(t, isText("text/plain; charset=utf-8"))
+	assert.True(t, isText("application/json"))
+	assert.False(t, isText("image/png"))
+	assert.False(t, isText("application/octet-stream"))
+}
+
+func (Suite) BeforeAll(t T) {
+	t.Log("before all")
+}
+
+func (Suite) CasesName() []string {
+	return []string{"small", "big"}
+}
+
+func (Suite) CasesSize() []int {
+	return []int{1, 10}
+}
+
+func (Suite) TestPass(t T) {
+	t.Log("hello from test")
+
+	t.Attach("body.txt", []byte("body"), "")
+
+	image, err := base64.StdEncoding.DecodeString(png)
+	require.NoError(t, err)
+
+	t.Attach("pixel.png", image, "image/png")
+}
+
+func (Suite) TestSteps(t T) {
+	testo.Run(t, "nested step", func(t T) {
+		t.Log("in step")
+	})
+}
+
+func (Suite) TestFlaky(t T) {
+	if testo.Inspect(t).Attempt == 1 {
+		t.Error("first attempt fails")
+	}
+}
+
+func (Suite) TestCases(T, struct {
+	Name string
+	Size int
+},
+) {
+}
+
+func (OtherSuite) TestSkip(t T) {
+	t.Skip("later")
+}
+
+func (OtherSuite) XTestLater(T) {}
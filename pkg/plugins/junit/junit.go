@@ -0,0 +1,448 @@
+// Package junit provides JUnit XML reports as a plugin for testo.
+package junit
+
+import (
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/eventtree"
+	"github.com/metafates/testo/internal/fsutil"
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/internal/reportutil"
+	"github.com/metafates/testo/plugin"
+)
+
+//nolint:gochecknoglobals // flags can be global
+var outputDir = flag.String(
+	"junit.output",
+	"junit-results",
+	"path to output dir for JUnit XML reports",
+)
+
+type option func(*JUnit)
+
+// WithOutputDir sets output directory for the reports.
+//
+// By default, it is "junit-results".
+func WithOutputDir(dir string) plugin.Option {
+	return plugin.Option{
+		Value: option(func(j *JUnit) {
+			j.outputDir = dir
+		}),
+	}
+}
+
+// WithSubtests reports subtests started by [testo.Run] as separate test cases.
+//
+// By default, subtests are reported as part of their suite test.
+func WithSubtests() plugin.Option {
+	return plugin.Option{
+		Value: option(func(j *JUnit) {
+			j.subtests = true
+		}),
+	}
+}
+
+// JUnit is a plugin which writes JUnit XML report of the suite
+// to <output>/<package>.<test>.xml file after the suite,
+// where package is the import path of the suite type
+// and test is the name of the suite run, e.g. TestAll/Suite.
+// Slashes in both are replaced with underscores.
+// So suites of different packages and several runs of the same suite
+// can write their reports to the same directory.
+//
+// Each suite test and parametrized case is reported as a test case,
+// including failure messages, skip reasons, panics, logs and durations.
+// Failed attempts of the retried tests are reported as flaky or rerun failures.
+type JUnit struct {
+	*testo.T
+
+	outputDir string
+	subtests  bool
+
+	// report is collected by the suite level plugin only.
+	report *report
+}
+
+// Init plugin.
+func (j *JUnit) Init(parent *JUnit, options ...plugin.Option) {
+	if parent != nil && parent.report != nil {
+		return
+	}
+
+	j.outputDir = *outputDir
+
+	for _, o := range options {
+		if o, ok := o.Value.(option); ok {
+			o(j)
+		}
+	}
+
+	j.report = newReport(j.subtests)
+}
+
+// Plugin implements [plugin.Plugin].
+func (j *JUnit) Plugin() plugin.Spec {
+	if j.report == nil {
+		return plugin.Spec{}
+	}
+
+	return plugin.Spec{
+		Observer: j.observe,
+	}
+}
+
+func (j *JUnit) observe(event plugin.Event) {
+	j.report.Add(event)
+
+	if _, ok := event.(plugin.SuiteFinished); !ok {
+		return
+	}
+
+	if err := j.write(); err != nil {
+		j.Errorf("junit: %v", err)
+	}
+}
+
+func (j *JUnit) write() error {
+	data, err := xml.MarshalIndent(j.report.Suites(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	data = append([]byte(xml.Header), data...)
+
+	return fsutil.WriteFileAtomic(
+		filepath.Join(j.outputDir, fileNameOf(j.report.tree)),
+		append(data, '\n'),
+	)
+}
+
+// fileNameOf returns the name of the report file of the suite run.
+func fileNameOf(tree *eventtree.Tree) string {
+	name := tree.Suite.Name
+
+	if typ := tree.SuiteType; typ != nil {
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		name = typ.PkgPath() + "." + name
+	}
+
+	return strings.ReplaceAll(name, "/", "_") + ".xml"
+}
+
+// report collects test cases of the suite from the events.
+type report struct {
+	subtests bool
+
+	tree *eventtree.Tree
+}
+
+func newReport(subtests bool) *report {
+	return &report{
+		subtests: subtests,
+		tree:     eventtree.New(),
+	}
+}
+
+// Add the event to the report.
+func (r *report) Add(event plugin.Event) {
+	r.tree.Add(event)
+}
+
+// Suites returns the report in JUnit format.
+func (r *report) Suites() testSuites {
+	root := r.tree.Suite
+
+	suite := testSuite{
+		Name:      r.tree.SuiteName,
+		Timestamp: root.Started.Format("2006-01-02T15:04:05"),
+		Time:      seconds(root.Result.Duration),
+	}
+
+	for _, c := range r.casesOf(root.Children) {
+		suite.Tests++
+
+		switch {
+		case c.Error != nil:
+			suite.Errors++
+
+		case c.Failure != nil:
+			suite.Failures++
+
+		case c.Skipped != nil:
+			suite.Skipped++
+		}
+
+		suite.TestCases = append(suite.TestCases, c)
+	}
+
+	var systemOut *cdata
+
+	if len(root.Logs) > 0 {
+		systemOut = &cdata{Text: strings.Join(root.Logs, "\n")}
+	}
+
+	// failures of the suite itself, e.g. in BeforeAll hook
+	if len(root.Failures) > 0 {
+		suite.Tests++
+		suite.Failures++
+
+		suite.TestCases = append(suite.TestCases, testCase{
+			Name:      r.tree.SuiteName,
+			Classname: r.tree.SuiteName,
+			Time:      seconds(root.Result.Duration),
+			Failure: &problem{
+				Message: reportutil.FirstLine(root.Failures),
+				Type:    failureType(root.Result.FailureKind),
+				Text:    strings.Join(root.Failures, "\n"),
+			},
+			SystemOut: systemOut,
+		})
+	}
+
+	suite.SystemOut = systemOut
+
+	return testSuites{
+		Name:     r.tree.SuiteName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []testSuite{suite},
+	}
+}
+
+// casesOf returns test cases of the tests,
+// followed by the cases of their subtests if they are reported separately.
+func (r *report) casesOf(tests []*eventtree.Test) []testCase {
+	var cases []testCase
+
+	for _, t := range tests {
+		cases = append(cases, r.testCase(t))
+
+		if r.subtests {
+			cases = append(cases, r.casesOf(t.Children)...)
+		}
+	}
+
+	return cases
+}
+
+func (r *report) testCase(t *eventtree.Test) testCase {
+	failures, logs := r.messagesOf(t, t)
+
+	c := testCase{
+		Name:      strings.TrimPrefix(t.Name, r.tree.Suite.Name+"/"),
+		Classname: r.tree.SuiteName,
+		Time:      seconds(t.Result.Duration),
+	}
+
+	if len(logs) > 0 {
+		c.SystemOut = &cdata{Text: strings.Join(logs, "\n")}
+	}
+
+	var properties []property
+
+	switch info := t.Info.(type) {
+	case plugin.RegularTestInfo:
+		properties = tagProperties(info.Tags)
+
+	case plugin.ParametrizedTestInfo:
+		properties = tagProperties(info.Tags)
+
+		names := maputil.Keys(info.Params)
+		slices.Sort(names)
+
+		for _, name := range names {
+			properties = append(properties, property{
+				Name:  "param." + name,
+				Value: fmt.Sprint(info.Params[name]),
+			})
+		}
+	}
+
+	c.Properties = properties
+
+	isFailed := t.Failed() || len(failures) > 0
+
+	switch {
+	case t.Result.Panic != nil:
+		c.Error = &problem{
+			Message: fmt.Sprintf("panic: %v", t.Result.Panic.Value),
+			Type:    "panic",
+			Text:    strings.Join(append(failures, t.Result.Panic.Trace), "\n\n"),
+		}
+
+	case isFailed:
+		c.Failure = &problem{
+			Message: reportutil.FirstLine(failures),
+			Type:    failureType(t.Result.FailureKind),
+			Text:    strings.Join(failures, "\n"),
+		}
+
+	case t.IsSkipped:
+		c.Skipped = &skipped{Message: t.Result.Message}
+	}
+
+	for _, attempt := range t.Retries {
+		a := r.testCase(attempt)
+
+		p := a.Failure
+		if p == nil {
+			p = a.Error
+		}
+
+		if p == nil {
+			continue
+		}
+
+		rerun := rerunFailure{
+			Message:    p.Message,
+			Type:       p.Type,
+			StackTrace: &cdata{Text: p.Text},
+			SystemOut:  a.SystemOut,
+		}
+
+		if isFailed {
+			c.RerunFailures = append(c.RerunFailures, rerun)
+		} else {
+			c.FlakyFailures = append(c.FlakyFailures, rerun)
+		}
+	}
+
+	return c
+}
+
+// messagesOf returns failures and logs of the test reported as part of the test case,
+// along with the ones of its subtests unless they are reported separately.
+// Messages of the subtests are prefixed with their name.
+func (r *report) messagesOf(t, owner *eventtree.Test) (failures, logs []string) {
+	prefix := ""
+	if t != owner {
+		prefix = strings.TrimPrefix(t.Name, owner.Name+"/") + ": "
+	}
+
+	for _, msg := range t.Failures {
+		failures = append(failures, prefix+msg)
+	}
+
+	for _, msg := range t.Logs {
+		logs = append(logs, prefix+msg)
+	}
+
+	if r.subtests {
+		return failures, logs
+	}
+
+	for _, child := range t.Children {
+		childFailures, childLogs := r.messagesOf(child, owner)
+
+		failures = append(failures, childFailures...)
+		logs = append(logs, childLogs...)
+	}
+
+	return failures, logs
+}
+
+func tagProperties(tags []string) []property {
+	var properties []property
+
+	for _, tag := range tags {
+		properties = append(properties, property{Name: "tag", Value: tag})
+	}
+
+	return properties
+}
+
+func failureType(kind plugin.TestFailureKind) string {
+	switch kind {
+	case plugin.TestFailureKindFatal:
+		return "fatal"
+
+	case plugin.TestFailureKindTimeout:
+		return "timeout"
+
+	default:
+		return "failure"
+	}
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+type testSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Suites   []testSuite `xml:"testsuite"`
+}
+
+type testSuite struct {
+	Name      string     `xml:"name,attr"`
+	Tests     int        `xml:"tests,attr"`
+	Failures  int        `xml:"failures,attr"`
+	Errors    int        `xml:"errors,attr"`
+	Skipped   int        `xml:"skipped,attr"`
+	Time      string     `xml:"time,attr"`
+	Timestamp string     `xml:"timestamp,attr"`
+	TestCases []testCase `xml:"testcase"`
+	SystemOut *cdata     `xml:"system-out,omitempty"`
+}
+
+type testCase struct {
+	Name          string         `xml:"name,attr"`
+	Classname     string         `xml:"classname,attr"`
+	Time          string         `xml:"time,attr"`
+	Properties    []property     `xml:"properties>property,omitempty"`
+	Failure       *problem       `xml:"failure,omitempty"`
+	Error         *problem       `xml:"error,omitempty"`
+	Skipped       *skipped       `xml:"skipped,omitempty"`
+	FlakyFailures []rerunFailure `xml:"flakyFailure,omitempty"`
+	RerunFailures []rerunFailure `xml:"rerunFailure,omitempty"`
+	SystemOut     *cdata         `xml:"system-out,omitempty"`
+}
+
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type problem struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// rerunFailure is a failed attempt of the retried test,
+// as reported by Maven Surefire.
+type rerunFailure struct {
+	Message    string `xml:"message,attr,omitempty"`
+	Type       string `xml:"type,attr"`
+	StackTrace *cdata `xml:"stackTrace,omitempty"`
+	SystemOut  *cdata `xml:"system-out,omitempty"`
+}
+
+// cdata is the text written as is, without escaping.
+type cdata struct {
+	Text string `xml:",cdata"`
+}
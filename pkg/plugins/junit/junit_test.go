@@ -0,0 +1,162 @@
+package junit
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/eventtree"
+	"github.com/metafates/testo/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type T = *struct {
+	*testo.T
+
+	JUnit
+}
+
+type Suite struct{}
+
+func TestJUnit(t *testing.T) {
+	dir := t.TempDir()
+
+	testo.RunSuite[*Suite, T](t,
+		WithOutputDir(dir),
+		testo.WithRetry(testo.RetryPolicy{Retries: 1}),
+	)
+
+	data, err := os.ReadFile(filepath.Join(
+		dir,
+		"github.com_metafates_testo_pkg_plugins_junit.TestJUnit_Suite.xml",
+	))
+	require.NoError(t, err)
+
+	var report testSuites
+
+	require.NoError(t, xml.Unmarshal(data, &report))
+
+	assert.Equal(t, 7, report.Tests)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Zero(t, report.Failures)
+	assert.Zero(t, report.Errors)
+
+	require.Len(t, report.Suites, 1)
+
+	cases := make(map[string]testCase)
+
+	for _, c := range report.Suites[0].TestCases {
+		assert.Equal(t, "Suite", c.Classname)
+		assert.NotEmpty(t, c.Time)
+
+		cases[c.Name] = c
+	}
+
+	assert.Equal(t, &cdata{Text: "hello"}, cases["TestPass"].SystemOut)
+
+	assert.Equal(t, &skipped{Message: "later"}, cases["TestSkip"].Skipped)
+
+	assert.Equal(t, []property{{Name: "param.N", Value: "2"}}, cases["TestCases_case_2"].Properties)
+
+	require.Len(t, cases["TestFlaky"].FlakyFailures, 1)
+	assert.Equal(t, rerunFailure{
+		Message:    "first attempt fails",
+		Type:       "failure",
+		StackTrace: &cdata{Text: "first attempt fails"},
+	}, cases["TestFlaky"].FlakyFailures[0])
+
+	require.Len(t, cases["TestPanic"].FlakyFailures, 1)
+	assert.Equal(t, "panic: boom", cases["TestPanic"].FlakyFailures[0].Message)
+	assert.Equal(t, "panic", cases["TestPanic"].FlakyFailures[0].Type)
+	assert.Contains(t, cases["TestPanic"].FlakyFailures[0].StackTrace.Text, "goroutine")
+
+	require.Len(t, cases["TestSub"].FlakyFailures, 1)
+	assert.Equal(t, rerunFailure{
+		Message:    "sub: sub fails",
+		Type:       "failure",
+		StackTrace: &cdata{Text: "sub: sub fails"},
+		SystemOut:  &cdata{Text: "sub: in subtest"},
+	}, cases["TestSub"].FlakyFailures[0])
+}
+
+func TestReport_Suites(t *testing.T) {
+	r := newReport(true)
+
+	r.tree.SuiteName = "Suite"
+	r.tree.Suite = &eventtree.Test{
+		Name:     "TestJUnit",
+		Failures: []string{"BeforeAll failed"},
+	}
+
+	r.tree.Suite.Children = []*eventtree.Test{{
+		Name:     "TestJUnit/TestFoo",
+		Failures: []string{"first\nsecond", "third"},
+		Result:   plugin.Result{FailureKind: plugin.TestFailureKindFatal},
+	}}
+
+	report := r.Suites()
+
+	assert.Equal(t, 2, report.Tests)
+	assert.Equal(t, 2, report.Failures)
+
+	assert.Equal(t, &problem{
+		Message: "first",
+		Type:    "fatal",
+		Text:    "first\nsecond\nthird",
+	}, report.Suites[0].TestCases[0].Failure)
+
+	assert.Equal(t, "Suite", report.Suites[0].TestCases[1].Name)
+	assert.Equal(t, "BeforeAll failed", report.Suites[0].TestCases[1].Failure.Text)
+}
+
+func TestFileNameOf(t *testing.T) {
+	tree := eventtree.New()
+
+	tree.SuiteType = reflect.TypeFor[*Suite]()
+	tree.Suite = &eventtree.Test{Name: "TestA/Suite"}
+
+	assert.Equal(t, "github.com_metafates_testo_pkg_plugins_junit.TestA_Suite.xml", fileNameOf(tree))
+
+	tree.Suite.Name = "TestA/Suite#01"
+
+	assert.Equal(t, "github.com_metafates_testo_pkg_plugins_junit.TestA_Suite#01.xml", fileNameOf(tree))
+}
+
+func (Suite) CasesN() []int {
+	return []int{1, 2}
+}
+
+func (Suite) TestPass(t T) {
+	t.Log("hello")
+}
+
+func (Suite) TestFlaky(t T) {
+	if testo.Inspect(t).Attempt == 1 {
+		t.Error("first attempt fails")
+	}
+}
+
+func (Suite) TestPanic(t T) {
+	if testo.Inspect(t).Attempt == 1 {
+		panic("boom")
+	}
+}
+
+func (Suite) TestSub(t T) {
+	testo.Run(t, "sub", func(t T) {
+		if testo.Inspect(t).Attempt == 1 {
+			t.Log("in subtest")
+			t.Error("sub fails")
+		}
+	})
+}
+
+func (Suite) TestSkip(t T) {
+	t.Skip("later")
+}
+
+func (Suite) TestCases(T, struct{ N int }) {}
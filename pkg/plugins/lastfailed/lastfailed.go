@@ -0,0 +1,327 @@
+// Package lastfailed provides re-running of previously failed tests as a plugin for testo.
+package lastfailed
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/fsutil"
+	"github.com/metafates/testo/plugin"
+)
+
+//nolint:gochecknoglobals // flags can be global
+var (
+	failedFirst = flag.Bool(
+		"testo.failed-first",
+		false,
+		"run previously failed suite tests first",
+	)
+
+	onlyFailed = flag.Bool(
+		"testo.only-failed",
+		false,
+		"run only previously failed suite tests, or all of them if none failed",
+	)
+)
+
+// DefaultCacheFile is the default path of the file where failed tests are stored,
+// relative to the package being tested.
+const DefaultCacheFile = ".testo/lastfailed.json"
+
+// cacheMu guards cache files, since suites may run in parallel.
+//
+//nolint:gochecknoglobals // shared by all suites
+var cacheMu sync.Mutex
+
+type option func(*LastFailed)
+
+// WithCacheFile sets the path of the file where failed tests are stored.
+// See [DefaultCacheFile].
+func WithCacheFile(path string) plugin.Option {
+	return plugin.Option{
+		Value: option(func(l *LastFailed) {
+			l.cacheFile = path
+		}),
+	}
+}
+
+// LastFailed is a plugin which stores failed suite tests after the run,
+// so that they can be run first with -testo.failed-first flag
+// or exclusively with -testo.only-failed flag next time.
+//
+// Tests are identified by their suite, name and case params.
+// Only tests which have run are updated in the cache,
+// so failures of the tests filtered out on the next run are kept.
+type LastFailed struct {
+	*testo.T
+
+	cacheFile string
+
+	// suite is shared by all tests of the suite.
+	suite *suiteRun
+}
+
+// suiteRun holds results of the suite run.
+type suiteRun struct {
+	mu sync.Mutex
+
+	// failed tests of the previous run.
+	failed []Test
+
+	// results of the tests which have run, by whether they failed.
+	results []result
+}
+
+type result struct {
+	Test   Test
+	Failed bool
+}
+
+// Test is an identity of the suite test.
+type Test struct {
+	// Name is the raw base name of the test, e.g. "TestFoo".
+	Name string `json:"name"`
+
+	// Case is the name of the parametrized test case, if named.
+	Case string `json:"case,omitempty"`
+
+	// Params of the parametrized test case, encoded as JSON.
+	// Params which can not be encoded are omitted.
+	// Property tests have no params.
+	Params map[string]json.RawMessage `json:"params,omitempty"`
+}
+
+// cache is the content of the cache file.
+// It maps suite names to their failed tests.
+type cache map[string][]Test
+
+// Init plugin.
+func (l *LastFailed) Init(parent *LastFailed, options ...plugin.Option) {
+	// tests record their results to the state of the suite
+	if parent != nil && parent.suite != nil {
+		l.cacheFile = parent.cacheFile
+		l.suite = parent.suite
+
+		return
+	}
+
+	l.cacheFile = DefaultCacheFile
+
+	for _, o := range options {
+		if o, ok := o.Value.(option); ok {
+			o(l)
+		}
+	}
+
+	l.suite = &suiteRun{}
+
+	c, err := readCache(l.cacheFile)
+	if err != nil {
+		l.Logf("lastfailed: ignoring cache: %v", err)
+
+		return
+	}
+
+	l.suite.failed = c[l.SuiteName()]
+}
+
+// Plugin implements [plugin.Plugin].
+func (l *LastFailed) Plugin() plugin.Spec {
+	return plugin.Spec{
+		Plan: plugin.Plan{
+			Modify: l.plan,
+		},
+		Hooks: plugin.Hooks{
+			AfterEach: plugin.Hook{
+				// run after other hooks which may fail the test
+				Priority: plugin.TryLast,
+				Func:     l.record,
+			},
+			AfterAll: plugin.Hook{Func: l.save},
+		},
+	}
+}
+
+func (l *LastFailed) plan(tests *[]plugin.PlannedTest) {
+	if !*failedFirst && !*onlyFailed {
+		return
+	}
+
+	if len(l.suite.failed) == 0 {
+		if *onlyFailed {
+			l.Log("lastfailed: no previously failed tests, running all")
+		}
+
+		return
+	}
+
+	isFailed := func(test plugin.PlannedTest) bool {
+		return slices.ContainsFunc(l.suite.failed, func(failed Test) bool {
+			return failed.matches(test.Info())
+		})
+	}
+
+	var failed, passed []plugin.PlannedTest
+
+	for _, test := range *tests {
+		if isFailed(test) {
+			failed = append(failed, test)
+		} else {
+			passed = append(passed, test)
+		}
+	}
+
+	if *onlyFailed {
+		l.Logf("lastfailed: running %d previously failed of %d tests", len(failed), len(*tests))
+
+		*tests = failed
+
+		return
+	}
+
+	l.Logf("lastfailed: running %d previously failed tests first", len(failed))
+
+	*tests = append(failed, passed...)
+}
+
+func (l *LastFailed) record() {
+	// skipped tests keep their previous result
+	if l.Skipped() && !l.Failed() {
+		return
+	}
+
+	test, ok := testOf(testo.Inspect(l).Test)
+	if !ok {
+		return
+	}
+
+	l.suite.mu.Lock()
+	defer l.suite.mu.Unlock()
+
+	l.suite.results = append(l.suite.results, result{
+		Test:   test,
+		Failed: l.Failed(),
+	})
+}
+
+func (l *LastFailed) save() {
+	l.suite.mu.Lock()
+	defer l.suite.mu.Unlock()
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	c, err := readCache(l.cacheFile)
+	if err != nil {
+		l.Errorf("lastfailed: %v", err)
+
+		return
+	}
+
+	failed := c[l.SuiteName()]
+
+	for _, r := range l.suite.results {
+		failed = slices.DeleteFunc(failed, func(t Test) bool {
+			return t.equal(r.Test)
+		})
+
+		if r.Failed {
+			failed = append(failed, r.Test)
+		}
+	}
+
+	if len(failed) == 0 {
+		delete(c, l.SuiteName())
+	} else {
+		c[l.SuiteName()] = failed
+	}
+
+	if err := writeCache(l.cacheFile, c); err != nil {
+		l.Errorf("lastfailed: %v", err)
+	}
+}
+
+// testOf returns identity of the test with the given info.
+func testOf(info plugin.TestInfo) (Test, bool) {
+	switch info := info.(type) {
+	case plugin.RegularTestInfo:
+		return Test{Name: info.RawBaseName}, true
+
+	case plugin.ParametrizedTestInfo:
+		test := Test{
+			Name: info.RawBaseName,
+			Case: info.CaseName,
+		}
+
+		// params of property tests are generated inputs, not cases
+		if info.Strategy == "" {
+			return test, true
+		}
+
+		for name, value := range info.Params {
+			data, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+
+			if test.Params == nil {
+				test.Params = make(map[string]json.RawMessage)
+			}
+
+			test.Params[name] = data
+		}
+
+		return test, true
+
+	default:
+		return Test{}, false
+	}
+}
+
+// equal states whether both identities are the same.
+func (t Test) equal(other Test) bool {
+	return t.Name == other.Name && t.Case == other.Case && reflect.DeepEqual(t.Params, other.Params)
+}
+
+// matches states whether the planned test with the given info has this identity.
+func (t Test) matches(info plugin.TestInfo) bool {
+	planned, ok := testOf(info)
+
+	return ok && t.equal(planned)
+}
+
+func readCache(path string) (cache, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(cache), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := make(cache)
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func writeCache(path string, c cache) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// written atomically, so that a crash or a concurrent run does not leave it truncated
+	return fsutil.WriteFileAtomic(path, data)
+}
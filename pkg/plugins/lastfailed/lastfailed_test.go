@@ -0,0 +1,98 @@
+package lastfailed
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/metafates/testo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type T = *struct {
+	*testo.T
+
+	LastFailed
+}
+
+type Suite struct{}
+
+var events []string
+
+func run(t *testing.T, name string) []string {
+	t.Helper()
+
+	require.NoError(t, flag.Set(name, "true"))
+	t.Cleanup(func() { _ = flag.Set(name, "false") })
+
+	events = nil
+
+	testo.RunSuite[*Suite, T](t)
+
+	require.NoError(t, flag.Set(name, "false"))
+
+	return events
+}
+
+func TestLastFailed(t *testing.T) {
+	// TODO: use t.Chdir() when go is updated
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	seed := cache{
+		"Suite": {
+			{Name: "TestB"},
+			{Name: "TestP", Params: map[string]json.RawMessage{"N": json.RawMessage("2")}},
+			{Name: "TestSkip"},
+		},
+		"Other": {{Name: "TestA"}},
+	}
+
+	require.NoError(t, writeCache(DefaultCacheFile, seed))
+
+	assert.Equal(t,
+		[]string{"TestB", "TestP 2", "TestA", "TestP 1", "TestP 3"},
+		run(t, "testo.failed-first"),
+	)
+
+	// passed tests are removed, skipped and other suites are kept
+	c, err := readCache(DefaultCacheFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, cache{
+		"Suite": {{Name: "TestSkip"}},
+		"Other": {{Name: "TestA"}},
+	}, c)
+
+	require.NoError(t, writeCache(DefaultCacheFile, seed))
+
+	assert.Equal(t, []string{"TestB", "TestP 2"}, run(t, "testo.only-failed"))
+
+	require.NoError(t, writeCache(DefaultCacheFile, cache{}))
+
+	// runs all tests if none failed
+	assert.Equal(t,
+		[]string{"TestA", "TestB", "TestP 1", "TestP 2", "TestP 3"},
+		run(t, "testo.only-failed"),
+	)
+}
+
+func (Suite) CasesN() []int {
+	return []int{1, 2, 3}
+}
+
+func (Suite) TestA(T) { events = append(events, "TestA") }
+func (Suite) TestB(T) { events = append(events, "TestB") }
+
+func (Suite) TestP(_ T, params struct{ N int }) {
+	events = append(events, fmt.Sprint("TestP ", params.N))
+}
+
+func (Suite) TestSkip(t T) { t.Skip() }
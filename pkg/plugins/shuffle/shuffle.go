@@ -0,0 +1,166 @@
+// Package shuffle provides randomized order of suite tests as a plugin for testo.
+package shuffle
+
+import (
+	"flag"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/plugin"
+)
+
+//nolint:gochecknoglobals // flags can be global
+var shuffleFlag = flag.String(
+	"testo.shuffle",
+	"off",
+	`randomize order of suite tests: "on" for a random seed, a number for the given seed, or "off"`,
+)
+
+//nolint:gochecknoglobals // seed is shared by all suites of the run
+var randomSeed = sync.OnceValue(rand.Uint64)
+
+type option func(*Shuffle)
+
+// WithCases makes parametrized cases shuffled as separate tests.
+// Otherwise, cases of the same test are run together in their order.
+func WithCases() plugin.Option {
+	return plugin.Option{
+		Value: option(func(s *Shuffle) {
+			s.withCases = true
+		}),
+	}
+}
+
+// Shuffle is a plugin which runs suite tests in random order
+// when -testo.shuffle flag is set, to find tests which depend on each other.
+//
+// The seed is logged after the suite and on each test failure.
+// Pass it to the flag to reproduce the order:
+//
+//	go test ./... -testo.shuffle=12345
+type Shuffle struct {
+	*testo.T
+
+	seed      uint64
+	isEnabled bool
+	withCases bool
+}
+
+// Init plugin.
+func (s *Shuffle) Init(parent *Shuffle, options ...plugin.Option) {
+	// tests are shuffled with the seed chosen for the suite
+	if parent != nil && parent.isEnabled {
+		s.seed = parent.seed
+		s.isEnabled = true
+
+		return
+	}
+
+	for _, o := range options {
+		if o, ok := o.Value.(option); ok {
+			o(s)
+		}
+	}
+
+	switch *shuffleFlag {
+	case "", "off":
+		return
+
+	case "on":
+		s.seed = randomSeed()
+
+	default:
+		seed, err := strconv.ParseUint(*shuffleFlag, 10, 64)
+		if err != nil {
+			s.Fatalf(`invalid -testo.shuffle flag %q: must be "on", "off" or a seed number`, *shuffleFlag)
+
+			return
+		}
+
+		s.seed = seed
+	}
+
+	s.isEnabled = true
+}
+
+// Plugin implements [plugin.Plugin].
+func (s *Shuffle) Plugin() plugin.Spec {
+	if !s.isEnabled {
+		return plugin.Spec{}
+	}
+
+	return plugin.Spec{
+		Plan: plugin.Plan{
+			Modify: s.shuffle,
+		},
+		Hooks: plugin.Hooks{
+			AfterEach:    plugin.Hook{Func: s.logSeedOnFailure},
+			AfterEachSub: plugin.Hook{Func: s.logSeedOnFailure},
+			AfterAll:     plugin.Hook{Func: s.logSeed},
+		},
+	}
+}
+
+// Seed used to shuffle the tests.
+// It is zero if shuffling is disabled.
+func (s *Shuffle) Seed() uint64 {
+	return s.seed
+}
+
+func (s *Shuffle) shuffle(tests *[]plugin.PlannedTest) {
+	r := rand.New(rand.NewPCG(s.seed, 0)) //nolint:gosec // not for security
+
+	if s.withCases {
+		r.Shuffle(len(*tests), func(i, j int) {
+			(*tests)[i], (*tests)[j] = (*tests)[j], (*tests)[i]
+		})
+
+		return
+	}
+
+	// group cases by their test, preserving the order
+	var (
+		groups  [][]plugin.PlannedTest
+		indices = make(map[string]int)
+	)
+
+	for _, test := range *tests {
+		name := test.Name()
+
+		if info, ok := test.Info().(plugin.ParametrizedTestInfo); ok {
+			name = info.RawBaseName
+		}
+
+		i, ok := indices[name]
+		if !ok {
+			i = len(groups)
+			indices[name] = i
+
+			groups = append(groups, nil)
+		}
+
+		groups[i] = append(groups[i], test)
+	}
+
+	r.Shuffle(len(groups), func(i, j int) {
+		groups[i], groups[j] = groups[j], groups[i]
+	})
+
+	*tests = (*tests)[:0]
+
+	for _, group := range groups {
+		*tests = append(*tests, group...)
+	}
+}
+
+func (s *Shuffle) logSeedOnFailure() {
+	if s.Failed() {
+		s.Logf("shuffle: tests were run in random order, reproduce with -testo.shuffle=%d", s.seed)
+	}
+}
+
+func (s *Shuffle) logSeed() {
+	s.Logf("shuffle: tests were run in random order, reproduce with -testo.shuffle=%d", s.seed)
+}
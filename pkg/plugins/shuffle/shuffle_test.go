@@ -0,0 +1,65 @@
+package shuffle
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/metafates/testo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type T = *struct {
+	*testo.T
+
+	Shuffle
+}
+
+type Suite struct{}
+
+var events []string
+
+func run(t *testing.T, shuffle string) []string {
+	t.Helper()
+
+	require.NoError(t, flag.Set("testo.shuffle", shuffle))
+	t.Cleanup(func() { _ = flag.Set("testo.shuffle", "off") })
+
+	events = nil
+
+	testo.RunSuite[*Suite, T](t)
+
+	return events
+}
+
+func TestShuffle(t *testing.T) {
+	declared := run(t, "off")
+
+	assert.Equal(t, []string{"TestA", "TestB", "TestC", "TestD", "TestE", "TestP 1", "TestP 2", "TestP 3"}, declared)
+
+	shuffled := run(t, "42")
+
+	assert.NotEqual(t, declared, shuffled)
+	assert.ElementsMatch(t, declared, shuffled)
+	assert.Equal(t, shuffled, run(t, "42"), "same seed must give the same order")
+
+	// cases are run together in their order
+	i := slices.Index(shuffled, "TestP 1")
+	assert.Equal(t, []string{"TestP 1", "TestP 2", "TestP 3"}, shuffled[i:i+3])
+}
+
+func (Suite) CasesN() []int {
+	return []int{1, 2, 3}
+}
+
+func (Suite) TestA(T) { events = append(events, "TestA") }
+func (Suite) TestB(T) { events = append(events, "TestB") }
+func (Suite) TestC(T) { events = append(events, "TestC") }
+func (Suite) TestD(T) { events = append(events, "TestD") }
+func (Suite) TestE(T) { events = append(events, "TestE") }
+
+func (Suite) TestP(_ T, params struct{ N int }) {
+	events = append(events, fmt.Sprint("TestP ", params.N))
+}
@@ -0,0 +1,411 @@
+// Package snapshot provides snapshot testing as a plugin for testo.
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/fsutil"
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/internal/update"
+	"github.com/metafates/testo/plugin"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Dir is the directory where snapshot files are stored,
+// relative to the package being tested.
+const Dir = "__snapshots__"
+
+//nolint:gochecknoglobals // config is immutable
+var config = spew.ConfigState{
+	Indent:                  "  ",
+	SortKeys:                true,
+	SpewKeys:                true,
+	DisablePointerAddresses: true,
+	DisableCapacities:       true,
+}
+
+// Snapshots is a snapshot testing plugin.
+//
+// Snapshots of all the suite tests are stored in a single
+// __snapshots__/<Suite>.snap file, which is written after the suite.
+type Snapshots struct {
+	*testo.T
+
+	// name of the test relative to the suite.
+	name string
+
+	// calls is the number of MatchSnapshot calls in this test.
+	calls int
+
+	file *file
+}
+
+// Init plugin.
+func (s *Snapshots) Init(parent *Snapshots, _ ...plugin.Option) {
+	// snapshot file is loaded once, by the suite level plugin
+	if parent == nil || parent.file == nil {
+		s.file = loadFile(filepath.Join(Dir, s.SuiteName()+".snap"), s.Name()+"/")
+
+		return
+	}
+
+	s.file = parent.file
+	s.name = strings.TrimPrefix(s.Name(), s.file.prefix)
+
+	s.file.run(s.name)
+}
+
+// Plugin implements [plugin.Plugin].
+func (s *Snapshots) Plugin() plugin.Spec {
+	return plugin.Spec{
+		Hooks: plugin.Hooks{
+			AfterAll: plugin.Hook{Func: s.afterAll},
+		},
+	}
+}
+
+// MatchSnapshot compares the value with its snapshot.
+// Test fails with the unified diff if they are not equal.
+//
+// Snapshots are named after the test and the number of the call within it,
+// e.g. "TestFoo 2" for the second call in TestFoo.
+// Missing snapshots are created.
+// Run tests with -testo.update flag to update the existing ones.
+//
+// Strings and []byte are stored as is, [json.RawMessage] is indented with sorted keys.
+// Other values are serialized with sorted map keys and without pointer addresses.
+func (s *Snapshots) MatchSnapshot(value any) {
+	s.Helper()
+
+	if s.file.err != nil {
+		s.Fatalf("snapshot: %v", s.file.err)
+
+		return
+	}
+
+	s.calls++
+
+	name := s.name + " " + strconv.Itoa(s.calls)
+	got := serialize(value)
+
+	want, ok := s.file.match(name, got)
+
+	switch {
+	case !ok:
+		s.Logf("snapshot: %q written", name)
+
+	case want != got && !update.Enabled():
+		s.Errorf(
+			"snapshot: %q does not match, run with -testo.update flag to update it\n%s",
+			name, diff(want, got),
+		)
+	}
+}
+
+func (s *Snapshots) afterAll() {
+	obsolete, unchecked, malformed := s.file.obsolete()
+
+	if len(malformed) > 0 {
+		s.Errorf(
+			"snapshot: malformed headers, must be %q:\n%s",
+			headerPrefix+"<test> <number>"+headerSuffix,
+			strings.Join(malformed, "\n"),
+		)
+	}
+
+	if len(obsolete) > 0 && !update.Enabled() {
+		s.Logf(
+			"snapshot: obsolete snapshots, run with -testo.update flag to remove them:\n%s",
+			strings.Join(obsolete, "\n"),
+		)
+	}
+
+	if len(unchecked) > 0 {
+		s.Logf(
+			"snapshot: snapshots of the tests which did not run, remove them if the tests were removed:\n%s",
+			strings.Join(unchecked, "\n"),
+		)
+	}
+
+	if err := s.file.write(); err != nil {
+		s.Errorf("snapshot: %v", err)
+	}
+}
+
+// file is the snapshot file of the suite.
+type file struct {
+	mu sync.Mutex
+
+	path string
+
+	// prefix of the test names to trim.
+	prefix string
+
+	// loaded snapshots by their names.
+	loaded map[string]string
+
+	// snapshots to be written.
+	snapshots map[string]string
+
+	// matched are names of the snapshots used by the tests.
+	matched map[string]struct{}
+
+	// ran are the names of the tests which have run.
+	ran map[string]struct{}
+
+	isChanged bool
+
+	// err is the error of loading the file.
+	err error
+}
+
+func loadFile(path, prefix string) *file {
+	f := &file{
+		path:      path,
+		prefix:    prefix,
+		snapshots: make(map[string]string),
+		matched:   make(map[string]struct{}),
+		ran:       make(map[string]struct{}),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		f.err = err
+
+		return f
+	}
+
+	f.loaded = parse(data)
+
+	for name, snapshot := range f.loaded {
+		f.snapshots[name] = snapshot
+	}
+
+	return f
+}
+
+// run marks the test with the given name as run.
+func (f *file) run(test string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ran[test] = struct{}{}
+}
+
+// match returns the snapshot with the given name, updating it with got
+// if it is missing or update is enabled.
+//
+// It reports false if the snapshot was missing.
+func (f *file) match(name, got string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.matched[name] = struct{}{}
+
+	want, ok := f.loaded[name]
+
+	if !ok || (update.Enabled() && want != got) {
+		f.snapshots[name] = got
+		f.isChanged = true
+	}
+
+	return want, ok
+}
+
+// obsolete returns names of the snapshots which were not matched.
+//
+// Snapshots of the tests which have run are obsolete and
+// removed if update is enabled.
+// Snapshots of the tests which have not run are unchecked,
+// since the tests may be filtered out.
+// Snapshots which names have no call number, e.g. edited by hand, are malformed.
+func (f *file) obsolete() (obsolete, unchecked, malformed []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for name := range f.loaded {
+		if _, ok := f.matched[name]; ok {
+			continue
+		}
+
+		i := strings.LastIndexByte(name, ' ')
+		if i == -1 {
+			malformed = append(malformed, name)
+
+			continue
+		}
+
+		test := name[:i]
+
+		if _, ok := f.ran[test]; !ok {
+			unchecked = append(unchecked, name)
+
+			continue
+		}
+
+		obsolete = append(obsolete, name)
+
+		if update.Enabled() {
+			delete(f.snapshots, name)
+
+			f.isChanged = true
+		}
+	}
+
+	slices.Sort(obsolete)
+	slices.Sort(unchecked)
+	slices.Sort(malformed)
+
+	return obsolete, unchecked, malformed
+}
+
+// write the file atomically, if it was changed.
+func (f *file) write() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	if !f.isChanged {
+		return nil
+	}
+
+	return fsutil.WriteFileAtomic(f.path, format(f.snapshots))
+}
+
+// Snapshot file consists of the snapshots sorted by name,
+// each starting with a header line:
+//
+//	-- TestFoo 1 --
+//	snapshot content
+//	-- TestFoo/subtest 1 --
+//	another snapshot
+//
+// Content lines which look like a header or start with a backslash are escaped with a backslash.
+const (
+	headerPrefix = "-- "
+	headerSuffix = " --"
+	escape       = `\`
+)
+
+func format(snapshots map[string]string) []byte {
+	var buf bytes.Buffer
+
+	names := maputil.Keys(snapshots)
+	slices.Sort(names)
+
+	for _, name := range names {
+		buf.WriteString(headerPrefix + name + headerSuffix + "\n")
+
+		for _, line := range strings.SplitAfter(snapshots[name], "\n") {
+			if strings.HasPrefix(line, headerPrefix) || strings.HasPrefix(line, escape) {
+				buf.WriteString(escape)
+			}
+
+			buf.WriteString(line)
+		}
+
+		if !strings.HasSuffix(snapshots[name], "\n") {
+			// marks that the snapshot has no trailing new line
+			buf.WriteString("\n" + escape + "\n")
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func parse(data []byte) map[string]string {
+	snapshots := make(map[string]string)
+
+	var (
+		name    string
+		content strings.Builder
+	)
+
+	flush := func() {
+		if name != "" {
+			snapshots[name] = content.String()
+		}
+
+		content.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, headerPrefix) && strings.HasSuffix(line, headerSuffix):
+			flush()
+
+			name = strings.TrimSuffix(strings.TrimPrefix(line, headerPrefix), headerSuffix)
+
+		case line == escape:
+			// snapshot has no trailing new line
+			s := strings.TrimSuffix(content.String(), "\n")
+
+			content.Reset()
+			content.WriteString(s)
+
+		default:
+			content.WriteString(strings.TrimPrefix(line, escape) + "\n")
+		}
+	}
+
+	flush()
+
+	return snapshots
+}
+
+func serialize(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+
+	case []byte:
+		return string(value)
+
+	case json.RawMessage:
+		// normalize, so that formatting and order of keys do not matter
+		var v any
+
+		if err := json.Unmarshal(value, &v); err != nil {
+			return string(value)
+		}
+
+		data, _ := json.MarshalIndent(v, "", "  ")
+
+		return string(data) + "\n"
+
+	default:
+		return config.Sdump(value)
+	}
+}
+
+func diff(want, got string) string {
+	d, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(want),
+		B:        difflib.SplitLines(got),
+		FromFile: "snapshot",
+		ToFile:   "got",
+		Context:  3,
+	})
+
+	return d
+}
@@ -0,0 +1,114 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/metafates/testo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type T = *struct {
+	*testo.T
+
+	Snapshots
+}
+
+type Suite struct{}
+
+func TestSnapshots(t *testing.T) {
+	// TODO: use t.Chdir() when go is updated
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	path := filepath.Join(dir, Dir, "Suite.snap")
+
+	testo.RunSuite[*Suite, T](t)
+
+	want := `-- TestFoo 1 --
+(struct { Name string; Tags map[string]int }) {
+  Name: (string) (len=3) "foo",
+  Tags: (map[string]int) (len=2) {
+    (string) (len=1) "a": (int) 1,
+    (string) (len=1) "b": (int) 2
+  }
+}
+-- TestFoo 2 --
+{
+  "a": 1,
+  "b": [
+    true
+  ]
+}
+-- TestFoo/sub 1 --
+\-- not a header
+no trailing new line
+\
+`
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got))
+
+	// obsolete snapshot of the test which has run and unchecked of the one which has not
+	require.NoError(t, os.WriteFile(path, append(got, "-- TestFoo 3 --\nold\n-- TestRemoved 1 --\nold\n"...), 0o600))
+
+	require.NoError(t, flag.Set("testo.update", "true"))
+	t.Cleanup(func() { _ = flag.Set("testo.update", "false") })
+
+	testo.RunSuite[*Suite, T](t)
+
+	got, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want+"-- TestRemoved 1 --\nold\n", string(got))
+}
+
+func (Suite) TestFoo(t T) {
+	t.MatchSnapshot(struct {
+		Name string
+		Tags map[string]int
+	}{
+		Name: "foo",
+		Tags: map[string]int{"b": 2, "a": 1},
+	})
+
+	t.MatchSnapshot(json.RawMessage(`{"b": [true], "a": 1}`))
+
+	testo.Run(t, "sub", func(t T) {
+		t.MatchSnapshot("-- not a header\nno trailing new line")
+	})
+}
+
+func TestFormat(t *testing.T) {
+	snapshots := map[string]string{
+		"TestA 1": "",
+		"TestA 2": "\n",
+		"TestB 1": "-- x --\n\\escaped\n\\",
+		"TestC 1": "multi\nline\n",
+	}
+
+	assert.Equal(t, snapshots, parse(format(snapshots)))
+}
+
+func TestFile_obsolete(t *testing.T) {
+	f := loadFile(filepath.Join(t.TempDir(), "missing.snap"), "")
+
+	f.loaded = parse([]byte("-- TestA 1 --\na\n-- TestA 2 --\nb\n-- TestB 1 --\nc\n-- edited --\nd\n"))
+
+	f.run("TestA")
+	f.match("TestA 1", "a\n")
+
+	obsolete, unchecked, malformed := f.obsolete()
+
+	assert.Equal(t, []string{"TestA 2"}, obsolete)
+	assert.Equal(t, []string{"TestB 1"}, unchecked)
+	assert.Equal(t, []string{"edited"}, malformed)
+}
@@ -0,0 +1,330 @@
+// Package tap provides Test Anything Protocol output as a plugin for testo.
+package tap
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/eventtree"
+	"github.com/metafates/testo/internal/reportutil"
+	"github.com/metafates/testo/plugin"
+)
+
+//nolint:gochecknoglobals // flags can be global
+var output = flag.String(
+	"tap.output",
+	"",
+	"path to the file to write TAP output to, disabled if empty",
+)
+
+//nolint:gochecknoglobals // shared by all suites
+var files reportutil.Files[[]suite]
+
+type option func(*TAP)
+
+// WithOutput sets the path of the file to write TAP output to.
+// It takes precedence over -tap.output flag.
+func WithOutput(path string) plugin.Option {
+	return plugin.Option{
+		Value: option(func(t *TAP) {
+			t.output = path
+		}),
+	}
+}
+
+// TAP is a plugin which writes Test Anything Protocol version 14 output
+// to the file given by -tap.output flag.
+//
+// Each suite is a subtest with its tests, parametrized cases grouped by their test,
+// and subtests nested in the same way as they are run.
+// Failures have YAML diagnostic blocks. Skipped tests have SKIP directive
+// and pending tests have TODO directive.
+//
+// Suites of the package are written to the same file, which is rewritten after each suite.
+type TAP struct {
+	*testo.T
+
+	output string
+
+	// tree is collected by the suite level plugin only.
+	tree *eventtree.Tree
+}
+
+// Init plugin.
+func (t *TAP) Init(parent *TAP, options ...plugin.Option) {
+	if parent != nil && parent.tree != nil {
+		return
+	}
+
+	t.output = *output
+
+	for _, o := range options {
+		if o, ok := o.Value.(option); ok {
+			o(t)
+		}
+	}
+
+	if t.output != "" {
+		t.tree = eventtree.New()
+	}
+}
+
+// Plugin implements [plugin.Plugin].
+func (t *TAP) Plugin() plugin.Spec {
+	if t.tree == nil {
+		return plugin.Spec{}
+	}
+
+	return plugin.Spec{
+		Observer: t.observe,
+	}
+}
+
+func (t *TAP) observe(event plugin.Event) {
+	t.tree.Add(event)
+
+	if _, ok := event.(plugin.SuiteFinished); !ok {
+		return
+	}
+
+	if err := write(t.output, newSuite(t.tree)); err != nil {
+		t.Errorf("tap: %v", err)
+	}
+}
+
+// suite is the rendered suite subtest.
+type suite struct {
+	name   string
+	failed bool
+
+	// body is the subtest body, indented.
+	body string
+
+	// diagnostic of the suite failure, indented.
+	diagnostic string
+}
+
+func newSuite(tree *eventtree.Tree) suite {
+	var body strings.Builder
+
+	writeTests(&body, tree.Suite.Children, indent)
+
+	s := suite{
+		name:   tree.SuiteName,
+		failed: tree.Suite.Failed(),
+		body:   body.String(),
+	}
+
+	if s.failed {
+		var diagnostic strings.Builder
+
+		writeDiagnostic(&diagnostic, tree.Suite, "")
+
+		s.diagnostic = diagnostic.String()
+	}
+
+	return s
+}
+
+// write the suite to the file at path, along with the suites written before.
+func write(path string, s suite) error {
+	return files.Write(path, func(suites *[]suite) ([]byte, error) {
+		*suites = append(*suites, s)
+
+		return render(*suites), nil
+	})
+}
+
+// render the suites as TAP output.
+func render(suites []suite) []byte {
+	var out strings.Builder
+
+	out.WriteString("TAP version 14\n")
+
+	for i, s := range suites {
+		out.WriteString("# Subtest: " + s.name + "\n")
+		out.WriteString(s.body)
+
+		writePoint(&out, "", !s.failed, i+1, s.name, "")
+
+		out.WriteString(s.diagnostic)
+	}
+
+	fmt.Fprintf(&out, "1..%d\n", len(suites))
+
+	return []byte(out.String())
+}
+
+// indent of the subtests.
+const indent = "    "
+
+// entry is a test or a group of parametrized test cases.
+type entry struct {
+	name  string
+	test  *eventtree.Test
+	cases []*eventtree.Test
+}
+
+// entriesOf groups cases of parametrized tests in order of their first case.
+func entriesOf(tests []*eventtree.Test) []*entry {
+	var entries []*entry
+
+	groups := make(map[string]*entry)
+
+	for _, test := range tests {
+		info, ok := test.Info.(plugin.ParametrizedTestInfo)
+
+		// property tests have no cases
+		if !ok || info.Strategy == "" {
+			entries = append(entries, &entry{name: test.RawBaseName(), test: test})
+
+			continue
+		}
+
+		group, ok := groups[info.RawBaseName]
+		if !ok {
+			group = &entry{name: info.RawBaseName}
+			groups[info.RawBaseName] = group
+
+			entries = append(entries, group)
+		}
+
+		group.cases = append(group.cases, test)
+	}
+
+	return entries
+}
+
+// writeTests writes test points of the tests, followed by the plan.
+func writeTests(w *strings.Builder, tests []*eventtree.Test, prefix string) {
+	entries := entriesOf(tests)
+
+	for i, e := range entries {
+		if e.test != nil {
+			writeTest(w, e.test, prefix, i+1, e.name)
+
+			continue
+		}
+
+		isOK := true
+
+		w.WriteString(prefix + "# Subtest: " + e.name + "\n")
+
+		for j, c := range e.cases {
+			writeTest(w, c, prefix+indent, j+1, c.CaseName())
+
+			isOK = isOK && !c.Failed()
+		}
+
+		fmt.Fprintf(w, "%s%s1..%d\n", prefix, indent, len(e.cases))
+
+		writePoint(w, prefix, isOK, i+1, e.name, "")
+	}
+
+	fmt.Fprintf(w, "%s1..%d\n", prefix, len(entries))
+}
+
+func writeTest(w *strings.Builder, test *eventtree.Test, prefix string, number int, name string) {
+	if len(test.Children) > 0 {
+		w.WriteString(prefix + "# Subtest: " + name + "\n")
+
+		writeTests(w, test.Children, prefix+indent)
+	}
+
+	var directive string
+
+	switch {
+	case test.IsPending():
+		directive = "TODO " + test.Result.Message
+
+	case test.IsSkipped:
+		directive = "SKIP " + test.Result.Message
+	}
+
+	// pending tests are not implemented yet, so they are not ok
+	writePoint(w, prefix, !test.Failed() && !test.IsPending(), number, name, directive)
+
+	if test.Failed() {
+		writeDiagnostic(w, test, prefix)
+	}
+}
+
+func writePoint(w *strings.Builder, prefix string, isOK bool, number int, name, directive string) {
+	w.WriteString(prefix)
+
+	if !isOK {
+		w.WriteString("not ")
+	}
+
+	fmt.Fprintf(w, "ok %d - %s", number, escape(name))
+
+	if directive != "" {
+		w.WriteString(" # " + escape(strings.TrimSpace(directive)))
+	}
+
+	w.WriteString("\n")
+}
+
+// escape the description of the test point.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "#", `\#`, "\n", " ").Replace(s)
+}
+
+// diagnostic is the YAML diagnostic block of the failed test.
+type diagnostic struct {
+	Message    string  `yaml:"message,omitempty"`
+	Severity   string  `yaml:"severity"`
+	Kind       string  `yaml:"kind"`
+	DurationMS float64 `yaml:"duration_ms"`
+	Attempts   int     `yaml:"attempts,omitempty"`
+	Panic      string  `yaml:"panic,omitempty"`
+	Stack      string  `yaml:"stack,omitempty"`
+}
+
+func writeDiagnostic(w *strings.Builder, test *eventtree.Test, prefix string) {
+	d := diagnostic{
+		Message:    strings.Join(test.Failures, "\n"),
+		Severity:   "fail",
+		Kind:       reportutil.FailureKind(test.Result.FailureKind),
+		DurationMS: float64(test.Result.Duration.Microseconds()) / 1000,
+	}
+
+	if d.Message == "" {
+		d.Message = test.Result.Message
+	}
+
+	if len(test.Retries) > 0 {
+		d.Attempts = len(test.Retries) + 1
+	}
+
+	if test.Result.Panic != nil {
+		d.Panic = fmt.Sprint(test.Result.Panic.Value)
+		d.Stack = test.Result.Panic.Trace
+	}
+
+	var data strings.Builder
+
+	enc := yaml.NewEncoder(&data)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(d); err != nil {
+		data.Reset()
+		data.WriteString("message: " + err.Error() + "\n")
+	}
+
+	prefix += "  "
+
+	w.WriteString(prefix + "---\n")
+
+	for _, line := range strings.SplitAfter(data.String(), "\n") {
+		if line != "" {
+			w.WriteString(prefix + line)
+		}
+	}
+
+	w.WriteString(prefix + "...\n")
+}
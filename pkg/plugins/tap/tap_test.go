@@ -0,0 +1,156 @@
+package tap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/internal/eventtree"
+	"github.com/metafates/testo/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type T = *struct {
+	*testo.T
+
+	TAP
+}
+
+type (
+	Suite      struct{}
+	OtherSuite struct{}
+)
+
+func TestTAP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "report.tap")
+
+	testo.RunSuite[*Suite, T](t, WithOutput(path))
+	testo.RunSuite[*OtherSuite, T](t, WithOutput(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, `TAP version 14
+# Subtest: Suite
+    ok 1 - TestPass
+    # Subtest: TestSub
+        ok 1 - first
+        ok 2 - second \# 2 # SKIP not now
+        1..2
+    ok 2 - TestSub
+    not ok 3 - XTestLater # TODO pending
+    # Subtest: TestCases
+        ok 1 - case_1
+        ok 2 - case_2
+        1..2
+    ok 4 - TestCases
+    # Subtest: TestNamed
+        ok 1 - small
+        ok 2 - big
+        1..2
+    ok 5 - TestNamed
+    1..5
+ok 1 - Suite
+# Subtest: OtherSuite
+    ok 1 - TestSkip # SKIP later
+    1..1
+ok 2 - OtherSuite
+1..2
+`, string(data))
+}
+
+func TestNewSuite(t *testing.T) {
+	tree := eventtree.New()
+
+	tree.Suite = &eventtree.Test{
+		Name:     "Suite",
+		Failures: []string{"BeforeAll failed"},
+		Result:   plugin.Result{FailureKind: plugin.TestFailureKindFatal},
+	}
+	tree.SuiteName = "Suite"
+
+	tree.Suite.Children = []*eventtree.Test{
+		{
+			BaseName: "TestFail",
+			Failures: []string{"first", "second"},
+			Retries:  []*eventtree.Test{{}},
+			Result: plugin.Result{
+				FailureKind: plugin.TestFailureKindSoft,
+				Duration:    1500 * time.Microsecond,
+			},
+		},
+		{
+			BaseName: "TestPanic",
+			Failures: []string{"panicked"},
+			Result: plugin.Result{
+				FailureKind: plugin.TestFailureKindFatal,
+				Panic:       &plugin.PanicInfo{Value: "boom", Trace: "goroutine 1"},
+			},
+		},
+	}
+
+	s := newSuite(tree)
+
+	assert.True(t, s.failed)
+
+	assert.Equal(t, `    not ok 1 - TestFail
+      ---
+      message: |-
+        first
+        second
+      severity: fail
+      kind: soft
+      duration_ms: 1.5
+      attempts: 2
+      ...
+    not ok 2 - TestPanic
+      ---
+      message: panicked
+      severity: fail
+      kind: fatal
+      duration_ms: 0
+      panic: boom
+      stack: goroutine 1
+      ...
+    1..2
+`, s.body)
+
+	assert.Equal(t, `  ---
+  message: BeforeAll failed
+  severity: fail
+  kind: fatal
+  duration_ms: 0
+  ...
+`, s.diagnostic)
+}
+
+func (Suite) CasesN() []int {
+	return []int{1, 2}
+}
+
+func (Suite) CasesSize() []testo.Case[string] {
+	return []testo.Case[string]{
+		{Name: "small", Value: "s"},
+		{Name: "big", Value: "b"},
+	}
+}
+
+func (Suite) TestPass(T) {}
+
+func (Suite) XTestLater(T) {}
+
+func (Suite) TestSub(t T) {
+	testo.Run(t, "first", func(T) {})
+	testo.Run(t, "second # 2", func(t T) { t.Skip("not now") })
+}
+
+func (Suite) TestCases(T, struct{ N int }) {}
+
+func (Suite) TestNamed(T, struct{ Size string }) {}
+
+func (OtherSuite) TestSkip(t T) {
+	t.Skip("later")
+}
@@ -0,0 +1,65 @@
+// Package yamlcases registers YAML format of case files for testo.
+//
+// Import it for side effects to load cases from ".yaml" and ".yml" files:
+//
+//	import _ "github.com/metafates/testo/pkg/yamlcases"
+package yamlcases
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/metafates/testo"
+)
+
+//nolint:gochecknoinits // format is registered on import, like image and database drivers
+func init() {
+	testo.RegisterCaseFileFormat(caseRows, ".yaml", ".yml")
+}
+
+// caseRows returns rows of the YAML case file:
+// a sequence of rows, keyed by their number starting from 1,
+// or a mapping, keyed by its keys.
+func caseRows(data []byte) ([]testo.CaseFileRow, error) {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		// yaml errors already include the line
+		return nil, err
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	root := doc.Content[0]
+
+	row := func(key string, node *yaml.Node) testo.CaseFileRow {
+		return testo.CaseFileRow{
+			Key:    key,
+			Line:   node.Line,
+			Decode: node.Decode,
+		}
+	}
+
+	var rows []testo.CaseFileRow
+
+	switch root.Kind {
+	case yaml.SequenceNode:
+		for i, node := range root.Content {
+			rows = append(rows, row(strconv.Itoa(i+1), node))
+		}
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			rows = append(rows, row(root.Content[i].Value, root.Content[i+1]))
+		}
+
+	default:
+		return nil, fmt.Errorf("line %d: must be a sequence or a mapping", root.Line)
+	}
+
+	return rows, nil
+}
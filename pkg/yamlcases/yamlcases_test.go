@@ -0,0 +1,72 @@
+package yamlcases
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/metafates/testo"
+	"github.com/metafates/testo/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	Name string `yaml:"name"`
+	Age  int    `yaml:"age"`
+}
+
+type Suite struct{}
+
+var events []string
+
+func TestYAML(t *testing.T) {
+	events = nil
+
+	testo.RunSuite[*Suite, *testo.T](t)
+
+	assert.ElementsMatch(t, []string{
+		"users_1 {Carol 41}",
+		"users_2 {Dave 19}",
+	}, events)
+}
+
+func (Suite) CaseFiles() map[string]string {
+	return map[string]string{"User": "users.yaml"}
+}
+
+func (Suite) TestUser(t *testo.T, params struct{ User user }) {
+	//nolint:forcetypeassert // test is parametrized
+	info := testo.Inspect(t).Test.(plugin.ParametrizedTestInfo)
+
+	events = append(events, fmt.Sprint(info.CaseName, " ", params.User))
+}
+
+func TestCaseRows(t *testing.T) {
+	rows, err := caseRows([]byte("alice:\n  name: Alice\nbob:\n  name: Bob\n  age: old\n"))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, "alice", rows[0].Key)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "bob", rows[1].Key)
+	assert.Equal(t, 4, rows[1].Line)
+
+	var u user
+
+	require.NoError(t, rows[0].Decode(&u))
+	assert.Equal(t, user{Name: "Alice"}, u)
+
+	require.EqualError(
+		t,
+		rows[1].Decode(&u),
+		"yaml: unmarshal errors:\n  line 5: cannot unmarshal !!str `old` into int",
+	)
+}
+
+func TestCaseRows_Errors(t *testing.T) {
+	_, err := caseRows([]byte("\n42\n"))
+	require.EqualError(t, err, "line 2: must be a sequence or a mapping")
+
+	_, err = caseRows([]byte("- name: [\n"))
+	require.ErrorContains(t, err, "yaml: line")
+}
@@ -0,0 +1,192 @@
+package plugin
+
+import (
+	"reflect"
+	"time"
+)
+
+// Observer is notified about the events of the suite run.
+//
+// Only observers of the suite level T, the one used by BeforeAll and AfterAll hooks,
+// are notified. They receive events of the suite and all its tests and subtests
+// in the order they happened. Observers are never called concurrently.
+//
+// Events reported by the observer itself, e.g. by calling t.Log,
+// are delivered after it returns.
+type Observer func(event Event)
+
+// Event is a enum which is one of
+// [SuiteStarted], [TestPlanned], [TestStarted], [Log],
+// [Failure], [Skip], [Cleanup], [TestFinished] or [SuiteFinished].
+//
+// Use a type switch to handle them:
+//
+//	switch e := event.(type) {
+//	case plugin.TestStarted:
+//		// ...
+//	case plugin.Failure:
+//		// ...
+//	}
+type Event interface {
+	// Meta returns information common for all the events.
+	Meta() EventMeta
+
+	isEvent()
+}
+
+// EventMeta is information common for all the events.
+type EventMeta struct {
+	// Time when the event happened.
+	Time time.Time
+
+	// Test which reported the event.
+	Test TestID
+}
+
+// Meta returns m.
+func (m EventMeta) Meta() EventMeta { return m }
+
+// TestID identifies the test within the suite run.
+type TestID struct {
+	// ID of the test, unique within the suite run.
+	// Suite itself has zero ID.
+	//
+	// Each attempt of the retried test has its own ID.
+	ID int
+
+	// ParentID is the ID of the parent test,
+	// which is zero for the suite tests.
+	ParentID int
+
+	// Name is the full name of the test, the same as t.Name().
+	Name string
+
+	// Info about the test.
+	// It is nil for the suite.
+	Info TestInfo
+
+	// Attempt is the number of the current suite test attempt, starting from 1.
+	// See [TInfo.Attempt].
+	Attempt int
+}
+
+// SuiteStarted is reported before the BeforeAll hook of the suite.
+type SuiteStarted struct {
+	EventMeta
+
+	// Suite name.
+	Suite string
+
+	// SuiteType is the type of the suite, e.g. *MySuite.
+	// It can be used to find the source location of the suite test methods.
+	SuiteType reflect.Type
+}
+
+// TestPlanned is reported by the suite for each test it is going to run,
+// in the planned order.
+type TestPlanned struct {
+	EventMeta
+
+	// Name of the planned test, relative to the suite.
+	Name string
+
+	// Info about the planned test.
+	Info TestInfo
+
+	// Skip is the reason the test will be skipped, if any.
+	Skip string
+}
+
+// TestStarted is reported before the BeforeEach or BeforeEachSub hook of the test.
+type TestStarted struct {
+	EventMeta
+}
+
+// Log is reported when the test logs a message, e.g. with t.Log.
+type Log struct {
+	EventMeta
+
+	// Message logged.
+	Message string
+}
+
+// Failure is reported when the test fails, e.g. with t.Error or on timeout.
+// The test may fail multiple times.
+type Failure struct {
+	EventMeta
+
+	// Kind of the failure.
+	Kind TestFailureKind
+
+	// Message of the failure.
+	// It is empty for t.Fail() and t.FailNow().
+	Message string
+}
+
+// Skip is reported when the test is skipped, e.g. with t.Skip.
+type Skip struct {
+	EventMeta
+
+	// Message of the skip.
+	// It is empty for t.SkipNow().
+	Message string
+}
+
+// Cleanup is reported before running the function registered with t.Cleanup.
+//
+// Cleanups are run after the test function returns,
+// so they are reported after [TestFinished] event of the test.
+type Cleanup struct {
+	EventMeta
+}
+
+// TestFinished is reported after the AfterEach or AfterEachSub hook of the test.
+type TestFinished struct {
+	EventMeta
+
+	// Result of the test.
+	// Result kind is [TestFailureKindNone] if the test passed or was skipped.
+	Result Result
+
+	// Skipped states whether the test was skipped.
+	Skipped bool
+}
+
+// SuiteFinished is reported after the AfterAll hook of the suite.
+type SuiteFinished struct {
+	EventMeta
+
+	// Result of the suite.
+	// It is failed if any of the suite tests failed.
+	Result Result
+}
+
+func (SuiteStarted) isEvent()  {}
+func (TestPlanned) isEvent()   {}
+func (TestStarted) isEvent()   {}
+func (Log) isEvent()           {}
+func (Failure) isEvent()       {}
+func (Skip) isEvent()          {}
+func (Cleanup) isEvent()       {}
+func (TestFinished) isEvent()  {}
+func (SuiteFinished) isEvent() {}
+
+func mergeObservers(plugins ...Spec) Observer {
+	observers := make([]Observer, 0, len(plugins))
+
+	for _, p := range plugins {
+		if p.Observer != nil {
+			observers = append(observers, p.Observer)
+		}
+	}
+
+	if len(observers) == 0 {
+		return nil
+	}
+
+	return func(event Event) {
+		for _, o := range observers {
+			o(event)
+		}
+	}
+}
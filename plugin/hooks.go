@@ -3,6 +3,7 @@ package plugin
 import (
 	"cmp"
 	"slices"
+	"time"
 )
 
 // HookPriority is the [Hook] priority.
@@ -43,6 +44,49 @@ func (h Hook) compare(other Hook) int {
 	return cmp.Compare(h.Priority, other.Priority)
 }
 
+// Result is the outcome of the test or the suite, passed to [ResultHook].
+type Result struct {
+	// FailureKind is the kind of the failure.
+	// It is [TestFailureKindNone] if the test did not fail.
+	FailureKind TestFailureKind
+
+	// Message reported when the test failed or was skipped.
+	// Multiple messages are joined with new lines.
+	//
+	// It may be empty, for example, when t.Fail() or t.SkipNow() were called.
+	Message string
+
+	// Panic is panic information.
+	// It is nil if the test did not panic.
+	Panic *PanicInfo
+
+	// Duration of the test including its hooks.
+	Duration time.Duration
+}
+
+// ResultHook is the plugin hook which receives the test or suite [Result].
+type ResultHook struct {
+	// Priority defines execution order.
+	// See [Hook.Priority].
+	Priority HookPriority
+
+	// Func to be run for this hook.
+	Func func(result Result)
+}
+
+// Run this hook.
+// Calling this function with nil [ResultHook.Func] is safe and no op.
+func (h ResultHook) Run(result Result) {
+	if h.Func != nil {
+		h.Func(result)
+	}
+}
+
+// compare hooks based on their priority.
+func (h ResultHook) compare(other ResultHook) int {
+	return cmp.Compare(h.Priority, other.Priority)
+}
+
 // Hooks defines all hooks a plugin can define.
 type Hooks struct {
 	// BeforeAll is called before all tests once.
@@ -62,6 +106,26 @@ type Hooks struct {
 
 	// AfterAll is called after all tests once.
 	AfterAll Hook
+
+	// OnFailure is called when the test or subtest fails,
+	// after its AfterEach or AfterEachSub hook.
+	//
+	// It is called for each failed attempt of the retried test.
+	// See [TInfo.Attempt].
+	OnFailure ResultHook
+
+	// OnSkip is called when the test or subtest is skipped without failing,
+	// after its AfterEach or AfterEachSub hook.
+	OnSkip ResultHook
+
+	// OnPanic is called when the test or subtest panics, after OnFailure hook.
+	OnPanic ResultHook
+
+	// OnSuiteFailure is called when any test of the suite
+	// or the suite itself fails, after AfterAll hook.
+	//
+	// Result message lists the failed tests if the suite did not report its own.
+	OnSuiteFailure ResultHook
 }
 
 func mergeHooks(plugins ...Spec) Hooks {
@@ -71,6 +135,10 @@ func mergeHooks(plugins ...Spec) Hooks {
 	afterEachSub := make([]Hook, 0, len(plugins))
 	afterEach := make([]Hook, 0, len(plugins))
 	afterAll := make([]Hook, 0, len(plugins))
+	onFailure := make([]ResultHook, 0, len(plugins))
+	onSkip := make([]ResultHook, 0, len(plugins))
+	onPanic := make([]ResultHook, 0, len(plugins))
+	onSuiteFailure := make([]ResultHook, 0, len(plugins))
 
 	for _, p := range plugins {
 		if h := p.Hooks.BeforeAll; h.Func != nil {
@@ -96,6 +164,22 @@ func mergeHooks(plugins ...Spec) Hooks {
 		if h := p.Hooks.AfterAll; h.Func != nil {
 			afterAll = append(afterAll, h)
 		}
+
+		if h := p.Hooks.OnFailure; h.Func != nil {
+			onFailure = append(onFailure, h)
+		}
+
+		if h := p.Hooks.OnSkip; h.Func != nil {
+			onSkip = append(onSkip, h)
+		}
+
+		if h := p.Hooks.OnPanic; h.Func != nil {
+			onPanic = append(onPanic, h)
+		}
+
+		if h := p.Hooks.OnSuiteFailure; h.Func != nil {
+			onSuiteFailure = append(onSuiteFailure, h)
+		}
 	}
 
 	run := func(hooks []Hook) func() {
@@ -108,6 +192,16 @@ func mergeHooks(plugins ...Spec) Hooks {
 		}
 	}
 
+	runResult := func(hooks []ResultHook) func(Result) {
+		slices.SortStableFunc(hooks, ResultHook.compare)
+
+		return func(result Result) {
+			for _, h := range hooks {
+				h.Run(result)
+			}
+		}
+	}
+
 	return Hooks{
 		BeforeAll:     Hook{Func: run(beforeAll)},
 		BeforeEach:    Hook{Func: run(beforeEach)},
@@ -115,5 +209,10 @@ func mergeHooks(plugins ...Spec) Hooks {
 		AfterEachSub:  Hook{Func: run(afterEachSub)},
 		AfterEach:     Hook{Func: run(afterEach)},
 		AfterAll:      Hook{Func: run(afterAll)},
+
+		OnFailure:      ResultHook{Func: runResult(onFailure)},
+		OnSkip:         ResultHook{Func: runResult(onSkip)},
+		OnPanic:        ResultHook{Func: runResult(onPanic)},
+		OnSuiteFailure: ResultHook{Func: runResult(onSuiteFailure)},
 	}
 }
@@ -14,6 +14,9 @@ const (
 	// TestFailureKindFatal states that test failed with fatal error.
 	// For example, t.FailNow() or t.Fatal() were called.
 	TestFailureKindFatal
+
+	// TestFailureKindTimeout states that test failed because it exceeded its timeout.
+	TestFailureKindTimeout
 )
 
 // TInfo is extra information about T.
@@ -30,6 +33,13 @@ type TInfo struct {
 
 	// FailureKind is test failure kind.
 	FailureKind TestFailureKind
+
+	// Attempt is the number of the current suite test attempt, starting from 1.
+	// It is greater than 1 when a failed test is retried.
+	//
+	// Subtests share attempt number with their test.
+	// It is zero outside suite tests, e.g. in BeforeAll hook.
+	Attempt int
 }
 
 // PanicInfo holds information for recovered panic.
@@ -56,13 +66,35 @@ type ParametrizedTestInfo struct {
 	//  func TestFoo(t T, params struct{ ... }) {}
 	//
 	// Testo will create a separate test for each case
-	// and name it "TestFoo_case_N" where N is the case number.
+	// and name it "TestFoo_case_N" where N is the case number,
+	// or "TestFoo/name" if cases are named.
 	// Therefore, t.BaseName() would also equal to "TestFoo_case_N",
 	// while this field would store "TestFoo".
 	RawBaseName string
 
+	// CaseName is the raw name of the current test case, as given by testo.Case.
+	// Names of the cases for multiple params are joined with underscore
+	// in order of the params struct fields
+	// and slashes in them are replaced by underscores,
+	// so that the case is a single segment of the test name.
+	// Duplicate names are made unique with _N suffix.
+	//
+	// It is empty if cases are not named.
+	CaseName string
+
 	// Params passed for the current test case.
+	//
+	// For property tests, it contains the "seed" used to generate inputs
+	// and, once the property is falsified, the shrunk input.
+	// Since it is updated while the test runs, it should be read after the test.
 	Params map[string]any
+
+	// Tags of the current test case.
+	// They include tags of the test declared by the suite "Tags" method.
+	Tags []string
+
+	// Strategy used to combine the cases, e.g. "cartesian" or "pairwise".
+	Strategy string
 }
 
 func (ParametrizedTestInfo) isTestInfo() {}
@@ -89,6 +121,14 @@ type RegularTestInfo struct {
 	//  - First level is the test method level.
 	//  - Second and more levels are subtests.
 	Level int
+
+	// Tags of the test declared by the suite "Tags" method.
+	// Subtests have no tags.
+	Tags []string
+
+	// Pending states that the test is declared with "XTest" or "Pending" prefix.
+	// Pending tests are skipped.
+	Pending bool
 }
 
 func (RegularTestInfo) isTestInfo() {}
@@ -26,7 +26,7 @@ type Option struct {
 }
 
 // Plugin is an interface that plugins implement to provide
-// [Plan], [Hooks] and [Overrides] to the tests.
+// [Plan], [Hooks], [Overrides] and [Observer] to the tests.
 type Plugin interface {
 	Plugin() Spec
 }
@@ -36,6 +36,7 @@ type Spec struct {
 	Plan      Plan
 	Hooks     Hooks
 	Overrides Overrides
+	Observer  Observer
 }
 
 // MergeSpecs multiple plugin specs into one.
@@ -44,6 +45,7 @@ func MergeSpecs(plugins ...Spec) Spec {
 		Plan:      mergePlans(plugins...),
 		Hooks:     mergeHooks(plugins...),
 		Overrides: mergeOverrides(plugins...),
+		Observer:  mergeObservers(plugins...),
 	}
 }
 
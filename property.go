@@ -0,0 +1,331 @@
+package testo
+
+import (
+	"maps"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/plugin"
+)
+
+// maxShrinkChecks limits how many inputs are checked while shrinking a counterexample.
+const maxShrinkChecks = 1000
+
+// suiteGen is a generator provided by GenXXX suite method.
+type suiteGen[Suite any] struct {
+	Provides reflect.Type
+	Func     func(Suite) anyGen
+}
+
+func suiteGensOf[Suite any, T fataller](t T) map[string]suiteGen[Suite] {
+	vt := reflect.TypeFor[Suite]()
+
+	gens := make(map[string]suiteGen[Suite])
+
+	for i := range vt.NumMethod() {
+		method := vt.Method(i)
+
+		const prefix = "Gen"
+
+		if !isTest(method.Name, prefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(method.Name, prefix)
+
+		isValidIn := method.Type.NumIn() == 1
+		isValidOut := method.Type.NumOut() == 1 &&
+			method.Type.Out(0).Implements(reflect.TypeFor[anyGen]())
+
+		if !isValidIn || !isValidOut {
+			t.Fatalf(
+				"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s() testo.Gen[V]",
+				vt, method.Name,
+			)
+
+			continue
+		}
+
+		//nolint:forcetypeassert // checked above
+		provides := reflect.Zero(method.Type.Out(0)).Interface().(anyGen).valueType()
+
+		gens[name] = suiteGen[Suite]{
+			Provides: provides,
+			Func: func(s Suite) anyGen {
+				//nolint:forcetypeassert // checked above
+				return method.Func.Call([]reflect.Value{reflect.ValueOf(s)})[0].Interface().(anyGen)
+			},
+		}
+	}
+
+	return gens
+}
+
+// suiteProperty is a property test defined by PropertyXXX suite method.
+//
+//	func (Suite) PropertyFoo(t T, params struct{ ... })
+//
+// Its params are generated by GenXXX suite methods.
+type suiteProperty[Suite any, T CommonT] struct {
+	Name     string
+	Method   reflect.Method
+	Args     testArgs
+	Gens     map[string]suiteGen[Suite]
+	Fixtures suiteFixtures[Suite, T]
+}
+
+// Probe checks the property for random inputs.
+//
+// Each input is checked with a new T and a suite clone,
+// along with BeforeEach and AfterEach hooks, and with failures captured.
+// Given suite must not be prepared by BeforeEach hook.
+// The first input which falsifies the property is shrunk.
+//
+// Params of the test are set to the seed and the falsifying input, if any.
+// It returns the function to run the test with,
+// which checks that input once again, reporting failures as usual.
+func (p suiteProperty[Suite, T]) Probe(s Suite, t T, hooks suiteHooks[Suite, T]) func(Suite, T) {
+	t.Helper()
+
+	seed := *seedFlag
+	if seed == 0 {
+		seed = rand.Uint64() //nolint:gosec // not used for security
+	}
+
+	iterations := *iterationsFlag
+
+	gens := make(map[string]anyGen, len(p.Gens))
+
+	for name, g := range p.Gens {
+		gens[name] = g.Func(s)
+	}
+
+	names := maputil.Keys(gens)
+	slices.Sort(names)
+
+	r := rand.New(rand.NewPCG(seed, 0)) //nolint:gosec // not used for security
+
+	var discarded int
+
+	for i := range iterations {
+		input := make(map[string]reflect.Value, len(names))
+
+		for _, name := range names {
+			input[name] = gens[name].generate(r)
+		}
+
+		isFailed, isDiscarded := p.check(s, t, hooks, input)
+
+		if isDiscarded {
+			discarded++
+
+			continue
+		}
+
+		if !isFailed {
+			continue
+		}
+
+		input, steps := p.shrink(s, t, hooks, gens, names, input)
+
+		p.setParams(t, seed, input)
+
+		return func(s Suite, t T) {
+			t.Logf(
+				"property falsified after %d inputs with seed %d, shrunk in %d steps",
+				i+1, seed, steps,
+			)
+
+			callTest(p.Method, p.Args, s, t, p.paramValue(input), p.Fixtures)
+
+			// failures of subtests are known once they finish
+			t.unwrap().closeSubtests()
+
+			if !t.Failed() {
+				t.Errorf("property was falsified by %v, but it holds when checked again", p.paramValue(input))
+			}
+		}
+	}
+
+	p.setParams(t, seed, nil)
+
+	return func(_ Suite, t T) {
+		if iterations > 0 && discarded == iterations {
+			t.Skipf("all %d inputs were discarded, seed %d", discarded, seed)
+		}
+
+		t.Logf("property holds for %d inputs (%d discarded) with seed %d", iterations-discarded, discarded, seed)
+	}
+}
+
+// check runs the test with the given input in probing mode.
+func (p suiteProperty[Suite, T]) check(
+	s Suite,
+	t T,
+	hooks suiteHooks[Suite, T],
+	input map[string]reflect.Value,
+) (isFailed, isDiscarded bool) {
+	lifetime := newLifetime(t.unwrap().Context())
+	defer lifetime.End()
+
+	inputT := construct(
+		t.unwrap().T,
+		&t,
+		func(inputT *actualT) {
+			inputT.info.Test = t.unwrap().info.Test
+			inputT.info.Attempt = t.unwrap().info.Attempt
+			inputT.captureFailures = true
+			inputT.probing = true
+			inputT.lifetime = lifetime
+		},
+	)
+
+	s = cloneSuite(s)
+
+	probe(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				inputT.unwrap().info.FailureKind = plugin.TestFailureKindFatal
+			}
+		}()
+
+		hooks.BeforeEach(s, inputT)
+		defer hooks.AfterEach(s, inputT)
+
+		callTest(p.Method, p.Args, s, inputT, p.paramValue(input), p.Fixtures)
+	})
+
+	isDiscarded = inputT.unwrap().discarded
+	isFailed = inputT.unwrap().info.FailureKind != plugin.TestFailureKindNone && !isDiscarded
+
+	return isFailed, isDiscarded
+}
+
+// shrink searches for the simplest input which still falsifies the property.
+// It returns the input found and number of successful shrinking steps.
+func (p suiteProperty[Suite, T]) shrink(
+	s Suite,
+	t T,
+	hooks suiteHooks[Suite, T],
+	gens map[string]anyGen,
+	names []string,
+	input map[string]reflect.Value,
+) (map[string]reflect.Value, int) {
+	var steps, checks int
+
+	for isShrunk := true; isShrunk; {
+		isShrunk = false
+
+	search:
+		for _, name := range names {
+			for _, candidate := range gens[name].shrink(input[name]) {
+				if checks >= maxShrinkChecks {
+					return input, steps
+				}
+
+				checks++
+
+				next := maps.Clone(input)
+				next[name] = candidate
+
+				if isFailed, _ := p.check(s, t, hooks, next); isFailed {
+					input = next
+					steps++
+					isShrunk = true
+
+					break search
+				}
+			}
+		}
+	}
+
+	return input, steps
+}
+
+// setParams updates test info with the seed and the given input.
+func (p suiteProperty[Suite, T]) setParams(t T, seed uint64, input map[string]reflect.Value) {
+	params := map[string]any{"seed": seed}
+
+	for name, value := range input {
+		params[name] = value.Interface()
+	}
+
+	t.unwrap().info.Test = plugin.ParametrizedTestInfo{
+		RawBaseName: p.Name,
+		Params:      params,
+	}
+}
+
+// paramValue returns the params struct value for the given input.
+func (p suiteProperty[Suite, T]) paramValue(input map[string]reflect.Value) reflect.Value {
+	value := reflect.New(p.Method.Type.In(p.Args.Params)).Elem()
+
+	for name, v := range input {
+		value.FieldByName(name).Set(v)
+	}
+
+	return value
+}
+
+func newPropertyTest[Suite any, T CommonT](
+	t T,
+	method reflect.Method,
+	args testArgs,
+	gens map[string]suiteGen[Suite],
+	fixtures suiteFixtures[Suite, T],
+	timeout time.Duration,
+) suiteTest[Suite, T] {
+	param := method.Type.In(args.Params)
+
+	requiredGens := make(map[string]suiteGen[Suite], param.NumField())
+
+	for i := range param.NumField() {
+		field := param.Field(i)
+
+		g, ok := gens[field.Name]
+		if !ok {
+			t.Fatalf(
+				"wrong param signature for %[1]s.%[2]s: Gen%[3]s for param %[3]q not found",
+				reflect.TypeFor[Suite](),
+				method.Name,
+				field.Name,
+			)
+
+			continue
+		}
+
+		if !g.Provides.AssignableTo(field.Type) {
+			t.Fatalf(
+				"wrong param signature for %[1]s.%[2]s: Gen%[3]s provides %[4]s values, not assignable to param %[3]q of type %[5]s",
+				reflect.TypeFor[Suite](),
+				method.Name,
+				field.Name,
+				g.Provides,
+				field.Type,
+			)
+		}
+
+		requiredGens[field.Name] = g
+	}
+
+	property := suiteProperty[Suite, T]{
+		Name:     method.Name,
+		Method:   method,
+		Args:     args,
+		Gens:     requiredGens,
+		Fixtures: fixtures,
+	}
+
+	return suiteTest[Suite, T]{
+		Name: method.Name,
+		Info: plugin.ParametrizedTestInfo{
+			RawBaseName: method.Name,
+		},
+		Probe:   property.Probe,
+		Timeout: timeout,
+	}
+}
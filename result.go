@@ -0,0 +1,119 @@
+package testo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/metafates/testo/plugin"
+)
+
+// sprintln formats its arguments like [testing.T.Log] does.
+func sprintln(args ...any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+// addFailure records the failure message and reports the failure.
+// Failure kind must be already set.
+//
+// Failures of the inputs checked by property tests are ignored.
+func (t *T) addFailure(msg string) {
+	if t.probing {
+		return
+	}
+
+	if msg != "" {
+		t.messagesMu.Lock()
+		t.failureMessages = append(t.failureMessages, msg)
+		t.messagesMu.Unlock()
+	}
+
+	kind := t.info.FailureKind
+
+	t.emit(func(meta plugin.EventMeta) plugin.Event {
+		return plugin.Failure{EventMeta: meta, Kind: kind, Message: msg}
+	})
+}
+
+// addSkip records the skip message and reports the skip.
+func (t *T) addSkip(msg string) {
+	t.messagesMu.Lock()
+	t.skipMessage = msg
+	t.messagesMu.Unlock()
+
+	t.emit(func(meta plugin.EventMeta) plugin.Event {
+		return plugin.Skip{EventMeta: meta, Message: msg}
+	})
+}
+
+// result returns the current result of this T.
+func (t *T) result() plugin.Result {
+	t.messagesMu.Lock()
+	defer t.messagesMu.Unlock()
+
+	result := plugin.Result{
+		FailureKind: t.info.FailureKind,
+		Panic:       t.info.Panic,
+		Duration:    time.Since(t.started),
+	}
+
+	switch {
+	case t.failed():
+		// failures of subtests are reported to testing.T directly
+		result.FailureKind = max(result.FailureKind, plugin.TestFailureKindSoft)
+		result.Message = strings.Join(t.failureMessages, "\n")
+
+	case t.T.Skipped():
+		result.Message = t.skipMessage
+	}
+
+	return result
+}
+
+// finish reports the result of the finished test or subtest
+// and runs plugin hooks for it.
+func (t *T) finish() {
+	result := t.result()
+
+	isSkipped := result.FailureKind == plugin.TestFailureKindNone && t.T.Skipped()
+
+	t.emit(func(meta plugin.EventMeta) plugin.Event {
+		return plugin.TestFinished{EventMeta: meta, Result: result, Skipped: isSkipped}
+	})
+
+	switch {
+	case result.FailureKind != plugin.TestFailureKindNone:
+		t.plugin.Hooks.OnFailure.Run(result)
+
+		if result.Panic != nil {
+			t.plugin.Hooks.OnPanic.Run(result)
+		}
+
+	case isSkipped:
+		t.plugin.Hooks.OnSkip.Run(result)
+	}
+}
+
+// finishSuite reports the result of the finished suite
+// and runs plugin hooks for it.
+func (t *T) finishSuite(outcomes *testOutcomes) {
+	result := t.result()
+
+	// tests of the detached suite T do not fail its testing.T, see [T.detach].
+	if t.detached.Load() != nil && result.FailureKind == plugin.TestFailureKindNone &&
+		len(outcomes.FailedTests()) > 0 {
+		result.FailureKind = plugin.TestFailureKindSoft
+	}
+
+	if result.FailureKind != plugin.TestFailureKindNone && result.Message == "" {
+		result.Message = "failed tests: " + strings.Join(outcomes.FailedTests(), ", ")
+	}
+
+	t.emit(func(meta plugin.EventMeta) plugin.Event {
+		return plugin.SuiteFinished{EventMeta: meta, Result: result}
+	})
+
+	if result.FailureKind != plugin.TestFailureKindNone {
+		t.plugin.Hooks.OnSuiteFailure.Run(result)
+	}
+}
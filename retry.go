@@ -0,0 +1,92 @@
+package testo
+
+import (
+	"flag"
+	"time"
+
+	"github.com/metafates/testo/plugin"
+)
+
+// RetryPolicy defines how failed suite tests are retried.
+//
+// Each attempt runs BeforeEach and AfterEach hooks (including plugin hooks)
+// with a new T and a new suite clone.
+// Failures of all but the last attempt are logged but not reported.
+type RetryPolicy struct {
+	// Retries is the maximum number of attempts after the first one.
+	Retries int
+
+	// Backoff returns delay before the given attempt.
+	// The first retry is the attempt number 2.
+	//
+	// Nil means no delay.
+	Backoff func(attempt int) time.Duration
+}
+
+// WithRetry sets retry policy for failed suite tests.
+// This option should be passed to the [RunSuite] call.
+//
+// It can be overridden with -testo.retries flag, e.g. -testo.retries=0 disables retries.
+func WithRetry(policy RetryPolicy) plugin.Option {
+	return plugin.Option{Value: retryOption(policy)}
+}
+
+// ConstantBackoff returns backoff for [RetryPolicy]
+// which waits the same delay before each attempt.
+func ConstantBackoff(delay time.Duration) func(attempt int) time.Duration {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff returns backoff for [RetryPolicy]
+// which doubles the delay with each attempt starting with base.
+func ExponentialBackoff(base time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		return base << max(attempt-2, 0)
+	}
+}
+
+type retryOption RetryPolicy
+
+// retryPolicyOf returns retry policy from the given options and flags.
+func retryPolicyOf(options []plugin.Option) RetryPolicy {
+	var policy RetryPolicy
+
+	for _, o := range options {
+		if o, ok := o.Value.(retryOption); ok {
+			policy = RetryPolicy(o)
+		}
+	}
+
+	if isFlagSet(flag.CommandLine, "testo.retries") {
+		policy.Retries = *retriesFlag
+	}
+
+	return policy
+}
+
+// delay returns the delay before the given attempt.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	if p.Backoff == nil {
+		return 0
+	}
+
+	return p.Backoff(attempt)
+}
+
+// probe runs f in a separate goroutine and waits for it to finish.
+//
+// It is used along with captured failures,
+// so that FailNow would stop only f and not the calling test.
+func probe(f func()) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		f()
+	}()
+
+	<-done
+}
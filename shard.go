@@ -0,0 +1,142 @@
+package testo
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/metafates/testo/plugin"
+)
+
+// shard is a part of the suite tests to run, see -testo.shard flag.
+type shard struct {
+	// Index of the shard, starting from 1.
+	Index int
+
+	// Total number of shards.
+	Total int
+}
+
+// parseShard parses shard in a form of "index/total", e.g. "2/8".
+func parseShard(s string) (shard, error) {
+	rawIndex, rawTotal, ok := strings.Cut(s, "/")
+	if !ok {
+		return shard{}, errors.New(`must be in a form of "index/total"`)
+	}
+
+	index, err := strconv.Atoi(rawIndex)
+	if err != nil {
+		return shard{}, fmt.Errorf("invalid index: %w", err)
+	}
+
+	total, err := strconv.Atoi(rawTotal)
+	if err != nil {
+		return shard{}, fmt.Errorf("invalid total: %w", err)
+	}
+
+	if total < 1 || index < 1 || index > total {
+		return shard{}, fmt.Errorf("index must be from 1 to %d", max(total, 1))
+	}
+
+	return shard{Index: index, Total: total}, nil
+}
+
+// Has states whether the test with the given key belongs to this shard.
+func (s shard) Has(key string) bool {
+	h := fnv.New64a()
+
+	_, _ = h.Write([]byte(key))
+
+	return h.Sum64()%uint64(s.Total) == uint64(s.Index-1)
+}
+
+// shardPlanOf returns the plan which keeps only the tests
+// of the shard given by -testo.shard flag.
+//
+// Tests are assigned to shards by hash of the suite and test names,
+// so that each parametrized case may be run by a different shard.
+// Tests which depend on each other are always assigned to the same shard.
+//
+// It prints how many tests were assigned to the shard to stderr,
+// so that it is visible without -v flag and regardless of plugins.
+func shardPlanOf(t fataller, suiteName string, dependencies map[string][]string) plugin.Plan {
+	if *shardFlag == "" {
+		return plugin.Plan{}
+	}
+
+	s, err := parseShard(*shardFlag)
+	if err != nil {
+		t.Fatalf("invalid -testo.shard flag %q: %v", *shardFlag, err)
+
+		return plugin.Plan{}
+	}
+
+	groups := dependencyGroups(dependencies)
+
+	return plugin.Plan{
+		Modify: func(tests *[]plugin.PlannedTest) {
+			total := len(*tests)
+
+			*tests = slices.DeleteFunc(*tests, func(test plugin.PlannedTest) bool {
+				key := test.Name()
+
+				if group, ok := groups[rawBaseNameOf(test.Info())]; ok {
+					key = group
+				}
+
+				return !s.Has(suiteName + "/" + key)
+			})
+
+			fmt.Fprintf(
+				os.Stderr,
+				"testo: %s: shard %d/%d: running %d of %d tests\n",
+				suiteName, s.Index, s.Total, len(*tests), total,
+			)
+		},
+	}
+}
+
+// dependencyGroups maps names of the tests with dependencies or dependents
+// to the name of the group of tests connected by dependencies.
+// The group is named after its first test in lexical order.
+func dependencyGroups(dependencies map[string][]string) map[string]string {
+	groups := make(map[string]string)
+
+	var find func(test string) string
+
+	find = func(test string) string {
+		group, ok := groups[test]
+		if !ok || group == test {
+			groups[test] = test
+
+			return test
+		}
+
+		group = find(group)
+		groups[test] = group
+
+		return group
+	}
+
+	for test, prerequisites := range dependencies {
+		for _, p := range prerequisites {
+			a, b := find(test), find(p)
+
+			if a > b {
+				a, b = b, a
+			}
+
+			groups[b] = a
+		}
+	}
+
+	for test := range groups {
+		find(test)
+	}
+
+	return groups
+}
@@ -0,0 +1,607 @@
+package testo
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/metafates/testo/internal/maputil"
+	"github.com/metafates/testo/internal/reflectutil"
+)
+
+// Strategy defines how values of parametrized test cases are combined.
+//
+// By default, tests are run with all possible combinations of values, see [Cartesian].
+// Strategy for individual tests can be set by the suite "Strategies" method:
+//
+//	func (Suite) Strategies() map[string]testo.Strategy {
+//		return map[string]testo.Strategy{
+//			"TestFoo": testo.Pairwise(),
+//			"TestBar": testo.Zip().Exclude(map[string]any{"Browser": "safari"}),
+//		}
+//	}
+type Strategy struct {
+	kind strategyKind
+
+	// n is the strength of n-wise strategy.
+	n int
+
+	include []map[string]any
+	exclude []map[string]any
+}
+
+type strategyKind int
+
+const (
+	strategyCartesian strategyKind = iota
+	strategyZip
+	strategyNWise
+)
+
+// Cartesian strategy combines every value of each param with every value of the others.
+// That is, it takes the Cartesian product of all the cases.
+//
+// This is the default strategy.
+func Cartesian() Strategy {
+	return Strategy{kind: strategyCartesian}
+}
+
+// Zip strategy combines values of the params by their index:
+// the first values of each param, then the second ones and so on.
+//
+// If params have different number of values,
+// extra values are ignored.
+func Zip() Strategy {
+	return Strategy{kind: strategyZip}
+}
+
+// Pairwise strategy is [NWise] strategy with n equal to 2.
+func Pairwise() Strategy {
+	return NWise(2)
+}
+
+// NWise strategy combines values so that every combination of
+// values of any n params is used at least once.
+//
+// It produces far fewer combinations than [Cartesian]
+// while still catching bugs caused by interaction of up to n params.
+// Combinations are chosen greedily and determenistically.
+func NWise(n int) Strategy {
+	if n < 1 {
+		panic(fmt.Sprintf("n-wise strategy requires positive n, got %d", n))
+	}
+
+	return Strategy{kind: strategyNWise, n: n}
+}
+
+// Include returns a strategy which also runs the given combinations.
+//
+// Each combination must specify values for all the params of the test.
+func (s Strategy) Include(params ...map[string]any) Strategy {
+	s.include = append(slices.Clone(s.include), params...)
+
+	return s
+}
+
+// Exclude returns a strategy which does not run the combinations
+// matching any of the given params.
+//
+// Combination matches if it has equal values for all the params specified.
+// For example, {"OS": "windows"} excludes all combinations with "windows" OS.
+func (s Strategy) Exclude(params ...map[string]any) Strategy {
+	s.exclude = append(slices.Clone(s.exclude), params...)
+
+	return s
+}
+
+// String returns the name of the strategy.
+func (s Strategy) String() string {
+	var name string
+
+	switch s.kind {
+	case strategyCartesian:
+		name = "cartesian"
+
+	case strategyZip:
+		name = "zip"
+
+	case strategyNWise:
+		if s.n == 2 {
+			name = "pairwise"
+		} else {
+			name = strconv.Itoa(s.n) + "-wise"
+		}
+	}
+
+	var modifiers []string
+
+	if len(s.include) > 0 {
+		modifiers = append(modifiers, fmt.Sprintf("%d included", len(s.include)))
+	}
+
+	if len(s.exclude) > 0 {
+		modifiers = append(modifiers, fmt.Sprintf("%d excluded", len(s.exclude)))
+	}
+
+	if len(modifiers) == 0 {
+		return name
+	}
+
+	return name + " (" + strings.Join(modifiers, ", ") + ")"
+}
+
+// suiteStrategiesOf returns test strategies declared by the "Strategies" suite method.
+func suiteStrategiesOf[Suite any, T fataller](t T) map[string]Strategy {
+	const name = "Strategies"
+
+	suite := reflect.TypeFor[Suite]()
+
+	method, ok := suite.MethodByName(name)
+	if !ok {
+		return nil
+	}
+
+	f, ok := method.Func.Interface().(func(Suite) map[string]Strategy)
+	if !ok {
+		t.Fatalf(
+			"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s() map[string]%[3]s",
+			suite, name, reflect.TypeFor[Strategy](),
+		)
+
+		return nil
+	}
+
+	strategies := f(reflectutil.Make[Suite]())
+
+	var unknown []string
+
+	for name := range strategies {
+		if m, ok := suite.MethodByName(name); !ok || !isTestMethod(m.Name) || isTest(m.Name, "Property") {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+
+		t.Fatalf(
+			"wrong strategies for %[1]s: tests %[2]s not found",
+			suite, strings.Join(unknown, ", "),
+		)
+	}
+
+	return strategies
+}
+
+// validate checks that include and exclude rules
+// match params of the given struct type.
+func (s Strategy) validate(t fataller, test string, param reflect.Type) {
+	check := func(rule string, params map[string]any, requireAll bool) {
+		for name, value := range params {
+			field, ok := param.FieldByName(name)
+			if !ok {
+				t.Fatalf(
+					"wrong strategy for %s: %s rule has unknown param %q",
+					test, rule, name,
+				)
+
+				continue
+			}
+
+			if value == nil || !reflect.TypeOf(value).AssignableTo(field.Type) {
+				t.Fatalf(
+					"wrong strategy for %s: %s rule value %#v is not assignable to param %q of type %s",
+					test, rule, value, name, field.Type,
+				)
+			}
+		}
+
+		if requireAll && len(params) != param.NumField() {
+			t.Fatalf(
+				"wrong strategy for %s: %s rule must specify values for all %d params",
+				test, rule, param.NumField(),
+			)
+		}
+	}
+
+	for _, params := range s.include {
+		check("include", params, true)
+	}
+
+	for _, params := range s.exclude {
+		check("exclude", params, false)
+	}
+}
+
+// combine returns combinations of the given cases values according to this strategy.
+func (s Strategy) combine(cases map[string][]suiteCaseValue) []map[string]suiteCaseValue {
+	isExcluded := func(c map[string]suiteCaseValue) bool {
+		return slices.ContainsFunc(s.exclude, func(params map[string]any) bool {
+			return matchesCase(c, params)
+		})
+	}
+
+	var combinations []map[string]suiteCaseValue
+
+	switch s.kind {
+	case strategyCartesian:
+		combinations = casesPermutations(cases)
+
+	case strategyZip:
+		combinations = casesZip(cases)
+
+	case strategyNWise:
+		// excluded combinations are avoided while covering,
+		// so that the remaining ones still cover all allowed tuples
+		combinations = casesNWise(cases, s.n, isExcluded)
+	}
+
+	combinations = slices.DeleteFunc(combinations, isExcluded)
+
+	// values of the included combinations not provided by CasesXXX funcs
+	included := make(map[string][]suiteCaseValue)
+
+	for _, params := range s.include {
+		combinations = append(combinations, includedCase(cases, included, params))
+	}
+
+	return combinations
+}
+
+// matchesCase states whether the combination has equal values for all the given params.
+// Combination missing any of the params does not match.
+func matchesCase(combination map[string]suiteCaseValue, params map[string]any) bool {
+	for name, value := range params {
+		v, ok := combination[name]
+		if !ok || !reflect.DeepEqual(v.Value.Interface(), value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// includedCase returns a combination for the given params.
+//
+// Values equal to the ones provided by CasesXXX funcs retain their names.
+// Other values are numbered after them and added to included,
+// so that equal values share the index and different ones do not.
+func includedCase(
+	cases map[string][]suiteCaseValue,
+	included map[string][]suiteCaseValue,
+	params map[string]any,
+) map[string]suiteCaseValue {
+	combination := make(map[string]suiteCaseValue, len(params))
+
+	for name, value := range params {
+		isEqual := func(v suiteCaseValue) bool {
+			return reflect.DeepEqual(v.Value.Interface(), value)
+		}
+
+		if idx := slices.IndexFunc(cases[name], isEqual); idx >= 0 {
+			combination[name] = cases[name][idx]
+
+			continue
+		}
+
+		if idx := slices.IndexFunc(included[name], isEqual); idx >= 0 {
+			combination[name] = included[name][idx]
+
+			continue
+		}
+
+		v := suiteCaseValue{
+			Value: reflect.ValueOf(value),
+			Index: len(cases[name]) + len(included[name]) + 1,
+		}
+
+		included[name] = append(included[name], v)
+		combination[name] = v
+	}
+
+	return combination
+}
+
+// casesZip returns combinations of values with the same index.
+func casesZip[V any](v map[string][]V) []map[string]V {
+	if len(v) == 0 {
+		return []map[string]V{{}}
+	}
+
+	n := -1
+
+	for _, values := range v {
+		if n == -1 || len(values) < n {
+			n = len(values)
+		}
+	}
+
+	result := make([]map[string]V, 0, n)
+
+	for i := range n {
+		combination := make(map[string]V, len(v))
+
+		for key, values := range v {
+			combination[key] = values[i]
+		}
+
+		result = append(result, combination)
+	}
+
+	return result
+}
+
+// casesNWise returns determenistic combinations of the given values
+// such that every combination of values of any n keys is present at least once.
+//
+// Combinations (including partial ones) for which isExcluded returns true are never returned.
+// Combinations of n values which are not a part of any allowed combination are not covered,
+// neither are the ones for which such combination is not found within the search budget.
+// Nil isExcluded allows all combinations.
+//
+//nolint:cyclop,funlen,gocognit // splitting it would make it even more complex
+func casesNWise[V any](v map[string][]V, n int, isExcluded func(map[string]V) bool) []map[string]V {
+	keys := maputil.Keys(v)
+	slices.Sort(keys)
+
+	if n >= len(keys) {
+		return casesPermutations(v)
+	}
+
+	for _, key := range keys {
+		if len(v[key]) == 0 {
+			return nil
+		}
+	}
+
+	// tuple is a combination of n keys (by their indices in keys)
+	// and their value indices, encoded as a string.
+	encode := func(keyIdx, valueIdx []int) string {
+		var sb strings.Builder
+
+		for i := range keyIdx {
+			fmt.Fprintf(&sb, "%d=%d;", keyIdx[i], valueIdx[i])
+		}
+
+		return sb.String()
+	}
+
+	var keyCombinations [][]int
+
+	var combineKeys func(start int, current []int)
+
+	combineKeys = func(start int, current []int) {
+		if len(current) == n {
+			keyCombinations = append(keyCombinations, slices.Clone(current))
+
+			return
+		}
+
+		for i := start; i < len(keys); i++ {
+			combineKeys(i+1, append(current, i))
+		}
+	}
+
+	combineKeys(0, nil)
+
+	type tuple struct {
+		Keys, Values []int
+	}
+
+	var (
+		uncoveredOrder []tuple
+		uncovered      = make(map[string]struct{})
+	)
+
+	for _, keyIdx := range keyCombinations {
+		values := make([]int, n)
+
+		var fill func(i int)
+
+		fill = func(i int) {
+			if i == n {
+				t := tuple{Keys: keyIdx, Values: slices.Clone(values)}
+
+				uncoveredOrder = append(uncoveredOrder, t)
+				uncovered[encode(t.Keys, t.Values)] = struct{}{}
+
+				return
+			}
+
+			for j := range v[keys[keyIdx[i]]] {
+				values[i] = j
+				fill(i + 1)
+			}
+		}
+
+		fill(0)
+	}
+
+	// covered returns tuples covered by the given row.
+	// Unassigned values of the row are -1, tuples with them are ignored.
+	covered := func(row []int) []string {
+		var tuples []string
+
+		for _, keyIdx := range keyCombinations {
+			values := make([]int, n)
+
+			isAssigned := true
+
+			for i, k := range keyIdx {
+				if row[k] == -1 {
+					isAssigned = false
+
+					break
+				}
+
+				values[i] = row[k]
+			}
+
+			if isAssigned {
+				tuples = append(tuples, encode(keyIdx, values))
+			}
+		}
+
+		return tuples
+	}
+
+	countUncovered := func(row []int) int {
+		var count int
+
+		for _, t := range covered(row) {
+			if _, ok := uncovered[t]; ok {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	// excluded states whether the assigned values of the row are excluded.
+	excluded := func(row []int) bool {
+		if isExcluded == nil {
+			return false
+		}
+
+		combination := make(map[string]V, len(keys))
+
+		for k, key := range keys {
+			if row[k] != -1 {
+				combination[key] = v[key][row[k]]
+			}
+		}
+
+		return isExcluded(combination)
+	}
+
+	// allowed returns values of the unassigned key k,
+	// which are not excluded along with the assigned values of the row.
+	allowed := func(row []int, k int) []int {
+		var values []int
+
+		for j := range v[keys[k]] {
+			row[k] = j
+
+			if !excluded(row) {
+				values = append(values, j)
+			}
+		}
+
+		row[k] = -1
+
+		return values
+	}
+
+	// maxSteps limits the number of rows tried to complete each tuple,
+	// since the search is exponential when excludes make it infeasible.
+	const maxSteps = 10_000
+
+	var steps int
+
+	// complete assigns unassigned values of the row, so that it is not excluded.
+	// The key with the fewest allowed values is assigned first,
+	// and values covering the most uncovered tuples are tried first.
+	// It reports false if there is no such assignment or the budget is exhausted.
+	var complete func(row []int) bool
+
+	complete = func(row []int) bool {
+		steps++
+
+		if steps > maxSteps || excluded(row) {
+			return false
+		}
+
+		var (
+			k          = -1
+			candidates []int
+		)
+
+		for i := range row {
+			if row[i] != -1 {
+				continue
+			}
+
+			values := allowed(row, i)
+
+			// some key has no allowed values, so the row can not be completed
+			if len(values) == 0 {
+				return false
+			}
+
+			if k == -1 || len(values) < len(candidates) {
+				k, candidates = i, values
+			}
+		}
+
+		if k == -1 {
+			return true
+		}
+
+		counts := make(map[int]int, len(candidates))
+
+		for _, j := range candidates {
+			row[k] = j
+
+			counts[j] = countUncovered(row)
+		}
+
+		slices.SortStableFunc(candidates, func(a, b int) int {
+			return counts[b] - counts[a]
+		})
+
+		for _, j := range candidates {
+			row[k] = j
+
+			if complete(row) {
+				return true
+			}
+		}
+
+		row[k] = -1
+
+		return false
+	}
+
+	var result []map[string]V
+
+	for _, next := range uncoveredOrder {
+		if _, ok := uncovered[encode(next.Keys, next.Values)]; !ok {
+			continue
+		}
+
+		row := make([]int, len(keys))
+
+		for i := range row {
+			row[i] = -1
+		}
+
+		for i, k := range next.Keys {
+			row[k] = next.Values[i]
+		}
+
+		steps = 0
+
+		if !complete(row) {
+			// tuple can not be covered by allowed combinations
+			delete(uncovered, encode(next.Keys, next.Values))
+
+			continue
+		}
+
+		for _, t := range covered(row) {
+			delete(uncovered, t)
+		}
+
+		combination := make(map[string]V, len(keys))
+
+		for k, key := range keys {
+			combination[key] = v[key][row[k]]
+		}
+
+		result = append(result, combination)
+	}
+
+	return result
+}
@@ -0,0 +1,240 @@
+package testo
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/metafates/testo/plugin"
+)
+
+// subtestGroup tracks the subtests of the suite test attempt.
+//
+// Parallel subtests are run only after their parent test function returns.
+// Suite test function returns only after AfterEach hook, which is too late.
+// So parallel subtests of the suite test are not paused by [testing.T.Parallel].
+// Instead, their parent continues once they call [T.Parallel],
+// and they wait for the group to be closed before AfterEach.
+//
+// Subtests are still run by [testing.T] of the suite test,
+// so they keep their names and -test.run patterns match them as usual.
+type subtestGroup struct {
+	// release is closed when the group is closed to let parallel subtests continue.
+	release chan struct{}
+
+	// running subtests of the group.
+	running sync.WaitGroup
+
+	children []*T
+}
+
+// parallelSlots limits the number of parallel subtests of suite tests
+// running at once to -test.parallel, as [testing.T.Parallel] does.
+//
+//nolint:gochecknoglobals // shared by all suites, like -test.parallel itself
+var parallelSlots = sync.OnceValue(func() chan struct{} {
+	n := runtime.GOMAXPROCS(0)
+
+	if f := flag.Lookup("test.parallel"); f != nil {
+		if v, err := strconv.Atoi(f.Value.String()); err == nil && v > 0 {
+			n = v
+		}
+	}
+
+	return make(chan struct{}, n)
+})
+
+// subtestGroup returns the group to run the subtest of this T in.
+// It returns nil if this T is not a suite test or its group is closed.
+func (t *T) subtestGroup() *subtestGroup {
+	if t.level() != 1 {
+		return nil
+	}
+
+	t.groupMu.Lock()
+	defer t.groupMu.Unlock()
+
+	if t.isGroupClosed {
+		return nil
+	}
+
+	if t.group == nil {
+		t.group = &subtestGroup{release: make(chan struct{})}
+	}
+
+	return t.group
+}
+
+// runGrouped runs the subtest of this T in the group.
+//
+// It returns once the subtest has finished or called [T.Parallel],
+// which closes the paused channel.
+func (t *T) runGrouped(
+	group *subtestGroup,
+	name string,
+	paused <-chan struct{},
+	f func(t *testing.T),
+) bool {
+	var (
+		ok       bool
+		finished = make(chan struct{})
+	)
+
+	group.running.Add(1)
+
+	// The subtest may outlive this call, so it is run in its own goroutine.
+	// It is still run by the test, since the group is closed before AfterEach.
+	go func() {
+		defer group.running.Done()
+
+		ok = t.T.Run(name, f)
+
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return ok
+
+	case <-paused:
+		return true
+	}
+}
+
+// pauseGrouped lets the parent of this grouped subtest continue
+// and waits for the group to be closed and a free parallel slot,
+// as [testing.T.Parallel] would do.
+func (t *T) pauseGrouped() {
+	close(t.paused)
+
+	t.parent.groupMu.Lock()
+	group := t.parent.group
+	t.parent.groupMu.Unlock()
+
+	if group != nil {
+		<-group.release
+	}
+
+	slots := parallelSlots()
+
+	slots <- struct{}{}
+
+	t.T.Cleanup(func() { <-slots })
+}
+
+// addSubtest registers the subtest run in the group.
+func (t *T) addSubtest(child *T) {
+	t.groupMu.Lock()
+	defer t.groupMu.Unlock()
+
+	if t.group != nil {
+		t.group.children = append(t.group.children, child)
+	}
+}
+
+// closeSubtests lets parallel subtests run in the group continue
+// and waits for all of them to finish.
+// Subtests started after that are not grouped.
+func (t *T) closeSubtests() {
+	t.groupMu.Lock()
+	group := t.group
+	t.group = nil
+	t.isGroupClosed = true
+	t.groupMu.Unlock()
+
+	if group == nil {
+		return
+	}
+
+	close(group.release)
+	group.running.Wait()
+
+	// Captured failures are not seen by [testing.T],
+	// so we have to propagate them manually.
+	if !t.captureFailures {
+		return
+	}
+
+	for _, child := range group.children {
+		child.propagateFailure()
+	}
+}
+
+// markCapturedFailure logs that this T has captured failure,
+// since [testing.T] reports it as passed.
+func (t *T) markCapturedFailure() {
+	if t.info.FailureKind != plugin.TestFailureKindNone {
+		t.T.Logf("FAIL: failure of attempt %d is not reported, the test is retried", t.info.Attempt)
+	}
+}
+
+// propagateFailure marks the parent failed if this T has captured failure.
+// It must be called once this T and all its subtests have finished.
+func (t *T) propagateFailure() {
+	if t.info.FailureKind != plugin.TestFailureKindNone {
+		t.parent.info.FailureKind = max(t.parent.info.FailureKind, plugin.TestFailureKindSoft)
+	}
+}
+
+// subtestNames names subtests of the suite test attempts.
+//
+// Attempts share [testing.T], which makes names of subtests unique
+// by appending #NN suffix to the names used by previous attempts.
+// Instead, subtests of each attempt are named as if it was the only one.
+type subtestNames struct {
+	mu sync.Mutex
+
+	// previous is the number of subtests with the given name run by previous attempts.
+	previous map[string]int
+
+	// current is the number of subtests with the given name run by the current attempt.
+	current map[string]int
+}
+
+func newSubtestNames() *subtestNames {
+	return &subtestNames{
+		previous: make(map[string]int),
+		current:  make(map[string]int),
+	}
+}
+
+// nextAttempt must be called before each attempt.
+func (n *subtestNames) nextAttempt() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for name, count := range n.current {
+		n.previous[name] += count
+	}
+
+	clear(n.current)
+}
+
+// add registers the subtest with the given name run by the current attempt.
+//
+// It returns the suffix appended to the name by [testing.T]
+// and the one which would be appended if there were no previous attempts.
+func (n *subtestNames) add(name string) (actual, expected string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	current := n.current[name]
+	total := n.previous[name] + current
+
+	n.current[name]++
+
+	return uniqueSuffix(total), uniqueSuffix(current)
+}
+
+// uniqueSuffix returns the suffix [testing.T] appends to the name
+// used by the given number of tests before.
+func uniqueSuffix(count int) string {
+	if count == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("#%02d", count)
+}
@@ -5,7 +5,9 @@ import (
 	"maps"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -27,11 +29,43 @@ type (
 		Name string
 		Info plugin.TestInfo
 		Run  func(Suite, T)
+
+		// Probe, if set, is called before the hooks with the suite not prepared by them.
+		// It returns the function to run the test with instead of Run.
+		// It is used by property tests to search for a falsifying input.
+		Probe func(Suite, T, suiteHooks[Suite, T]) func(Suite, T)
+
+		// Timeout of the test declared by the suite.
+		// Zero means no timeout.
+		Timeout time.Duration
+
+		// Skip is the reason to skip this test without running it.
+		// Empty string means the test is not skipped.
+		Skip string
+
+		// Sequential states that the test is not run in parallel,
+		// since other tests depend on it, see [dependencyOrder].
+		Sequential bool
 	}
 
 	suiteCase[Suite any] struct {
 		Provides reflect.Type
-		Func     func(Suite) []reflect.Value
+		Func     func(Suite) []suiteCaseValue
+
+		// File to load the cases from.
+		// If set, Provides and Func are nil until the case
+		// is resolved for the param type, see [suiteCase.resolve].
+		File *caseFile
+	}
+
+	// suiteCaseValue is a single value provided by CasesXXX func.
+	suiteCaseValue struct {
+		caseInfo
+
+		Value reflect.Value
+
+		// Index of this value among others provided by the same func, starting from 1.
+		Index int
 	}
 )
 
@@ -73,6 +107,30 @@ func isTest(name, prefix string) bool {
 	return !unicode.IsLower(r)
 }
 
+// isTestMethod states whether name is a name of the suite test method,
+// including property, focused and pending tests.
+func isTestMethod(name string) bool {
+	return strings.HasPrefix(name, "Test") ||
+		isTest(name, "Property") ||
+		isFocusedTest(name) ||
+		isPendingTest(name)
+}
+
+// isFocusedTest states whether name is a name of the focused test method.
+//
+//	FTestFoo => true
+func isFocusedTest(name string) bool {
+	return isTest(name, "FTest")
+}
+
+// isPendingTest states whether name is a name of the pending test method.
+//
+//	XTestFoo   => true
+//	PendingFoo => true
+func isPendingTest(name string) bool {
+	return isTest(name, "XTest") || isTest(name, "Pending")
+}
+
 func suiteCasesOf[Suite any, T fataller](t T) map[string]suiteCase[Suite] {
 	vt := reflect.TypeFor[Suite]()
 
@@ -90,18 +148,18 @@ func suiteCasesOf[Suite any, T fataller](t T) map[string]suiteCase[Suite] {
 		name := strings.TrimPrefix(method.Name, prefix)
 
 		isValidIn := method.Type.NumIn() == 1
-		isValidOut := method.Type.NumOut() == 1 && method.Type.Out(0).Kind() == reflect.Slice
+		isValidOut := method.Type.NumOut() == 1 && isCasesType(method.Type.Out(0))
 
 		if !isValidIn || !isValidOut {
 			t.Fatalf(
-				"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s() []...",
+				"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s() []V, []testo.Case[V] or map[string]V",
 				reflect.TypeFor[Suite](), method.Name, t,
 			)
 		}
 
 		cases[name] = suiteCase[Suite]{
-			Provides: method.Type.Out(0).Elem(),
-			Func: func(s Suite) []reflect.Value {
+			Provides: casesValueType(method.Type.Out(0)),
+			Func: func(s Suite) []suiteCaseValue {
 				var suite reflect.Value
 
 				if method.Type.In(0).Kind() == reflect.Pointer &&
@@ -111,24 +169,126 @@ func suiteCasesOf[Suite any, T fataller](t T) map[string]suiteCase[Suite] {
 					suite = reflect.ValueOf(s)
 				}
 
-				slice := method.Func.Call([]reflect.Value{suite})[0]
-
-				values := make([]reflect.Value, 0, slice.Len())
+				return caseValuesOf(method.Func.Call([]reflect.Value{suite})[0])
+			},
+		}
+	}
 
-				for i := range slice.Len() {
-					v := slice.Index(i)
+	files := suiteCaseFilesOf[Suite](t)
 
-					values = append(values, v)
-				}
+	params := maputil.Keys(files)
+	slices.Sort(params)
 
-				return values
-			},
+	for _, param := range params {
+		if _, ok := cases[param]; ok {
+			t.Fatalf(
+				"wrong case files for %[1]s: param %[2]q has both Cases%[2]s method and case file",
+				vt, param,
+			)
 		}
+
+		cases[param] = suiteCase[Suite]{File: files[param]}
 	}
 
 	return cases
 }
 
+// resolve returns the case for the param of the given type.
+//
+// Cases loaded from file are decoded into values of that type,
+// failing with the file and line of the row which can not be decoded.
+func (c suiteCase[Suite]) resolve(t fataller, test, param string, typ reflect.Type) suiteCase[Suite] {
+	if c.File == nil {
+		return c
+	}
+
+	if _, err := c.File.values(typ); err != nil {
+		t.Fatalf(
+			"wrong param signature for %[1]s.%[2]s: param %[3]q: %[4]v",
+			reflect.TypeFor[Suite](), test, param, err,
+		)
+	}
+
+	file := c.File
+
+	return suiteCase[Suite]{
+		Provides: typ,
+		Func: func(Suite) []suiteCaseValue {
+			// decoded again so that tests do not share values,
+			// errors are checked above
+			values, _ := file.values(typ)
+
+			return values
+		},
+	}
+}
+
+// isCasesType states whether the given type can be returned by CasesXXX func.
+func isCasesType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice:
+		return true
+
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String
+
+	default:
+		return false
+	}
+}
+
+// casesValueType returns the type of case values for the type returned by CasesXXX func.
+func casesValueType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Slice && t.Elem().Implements(reflect.TypeFor[anyCase]()) {
+		field, _ := t.Elem().FieldByName("Value")
+
+		return field.Type
+	}
+
+	return t.Elem()
+}
+
+// caseValuesOf returns case values of the value returned by CasesXXX func.
+func caseValuesOf(cases reflect.Value) []suiteCaseValue {
+	values := make([]suiteCaseValue, 0, cases.Len())
+
+	if cases.Kind() == reflect.Map {
+		keys := cases.MapKeys()
+
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+
+		for i, key := range keys {
+			values = append(values, suiteCaseValue{
+				caseInfo: caseInfo{Name: key.String()},
+				Value:    cases.MapIndex(key),
+				Index:    i + 1,
+			})
+		}
+
+		return values
+	}
+
+	for i := range cases.Len() {
+		v := cases.Index(i)
+
+		value := suiteCaseValue{
+			Value: v,
+			Index: i + 1,
+		}
+
+		if c, ok := v.Interface().(anyCase); ok {
+			value.caseInfo = c.caseInfo()
+			value.Value = v.FieldByName("Value")
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
 // suiteHooksOf returns hooks of the given suite.
 func suiteHooksOf[Suite any, T fataller](t T) suiteHooks[Suite, T] {
 	return suiteHooks[Suite, T]{
@@ -193,9 +353,22 @@ func cloneSuite[Suite any](suite Suite) Suite {
 //
 // That's why we statically analyze parametrized tests signatures,
 // but delay the actual collection for later.
-type suiteTests[Suite any, T CommonT] struct {
+type suiteTests[Suite any, T any] struct {
 	Regular      []suiteTest[Suite, T]
 	Parametrized []func(s Suite) []suiteTest[Suite, T]
+
+	// Fixtures are types of the fixtures required by the tests.
+	Fixtures []reflect.Type
+
+	// Dependencies maps raw base names of the tests
+	// to the names of the tests they depend on.
+	Dependencies map[string][]string
+
+	// Tags maps raw base names of the tests to their tags.
+	Tags map[string][]string
+
+	// Plans are built-in plans applied before the plans of plugins.
+	Plans []plugin.Plan
 }
 
 // Get all suite tests.
@@ -203,134 +376,370 @@ type suiteTests[Suite any, T CommonT] struct {
 // Suite instance is required here to get
 // parameter cases (CasesXXX funcs), not to invoke the actual tests.
 func (st suiteTests[Suite, T]) Get(s Suite) []suiteTest[Suite, T] {
-	tests := st.Regular
+	tests := slices.Clone(st.Regular)
 
 	for _, p := range st.Parametrized {
 		tests = append(tests, p(s)...)
 	}
 
+	for i, test := range tests {
+		tests[i] = withTags(test, st.Tags[rawBaseNameOf(test.Info)])
+	}
+
 	return tests
 }
 
+// testArgs describes arguments of the test method which follow T.
+type testArgs struct {
+	// Params is the index of params struct argument.
+	// It is -1 for non-parametrized tests.
+	Params int
+
+	// Fixtures maps argument index to the type of fixture it requires.
+	Fixtures map[int]reflect.Type
+}
+
 //nolint:cyclop,funlen // splitting it would make it even more complex
 func testsFor[Suite any, T CommonT](
 	t T,
 	cases map[string]suiteCase[Suite],
+	gens map[string]suiteGen[Suite],
+	fixtures suiteFixtures[Suite, T],
 ) suiteTests[Suite, T] {
 	vt := reflect.TypeFor[Suite]()
 
 	var tests suiteTests[Suite, T]
 
+	timeouts := suiteTimeoutsOf[Suite](t)
+	strategies := suiteStrategiesOf[Suite](t)
+
+	tests.Dependencies = suiteDependenciesOf[Suite](t)
+	tests.Tags = suiteTagsOf[Suite](t)
+
+	requiredFixtures := make(map[reflect.Type]struct{})
+
+	focused := focusedTestsOf[Suite](t)
+
 	for i := range vt.NumMethod() {
 		method := vt.Method(i)
 
-		if !strings.HasPrefix(method.Name, "Test") {
+		isProperty := isTest(method.Name, "Property")
+
+		if !isTestMethod(method.Name) {
+			continue
+		}
+
+		if len(focused) > 0 && !isFocusedTest(method.Name) {
 			continue
 		}
 
 		raiseWrongSignatureError := func() {
+			if isProperty {
+				t.Fatalf(
+					"wrong signature for %[1]s.%[2]s, must be: func %[1]s.%[2]s(%[3]s, struct{...}, [fixtures...])",
+					vt,
+					method.Name,
+					reflect.TypeFor[T](),
+				)
+
+				return
+			}
+
 			t.Fatalf(
-				"wrong signature for %[1]s.%[2]s, must be: func %[1]s.%[2]s(%[3]s) or func %[1]s.%[2]s(%[3]s, struct{...})",
+				"wrong signature for %[1]s.%[2]s, must be: func %[1]s.%[2]s(%[3]s, [struct{...}], [fixtures...])",
 				vt,
 				method.Name,
 				reflect.TypeFor[T](),
 			)
 		}
 
-		if method.Type.NumOut() != 0 {
+		args, missing, ok := testArgsOf(method, fixtures)
+		if !ok {
+			if missing != nil {
+				t.Fatalf(
+					"wrong signature for %[1]s.%[2]s: no fixture provides %[3]s",
+					vt, method.Name, missing,
+				)
+			}
+
 			raiseWrongSignatureError()
 		}
 
-		if method.Type.NumIn() < 2 {
-			raiseWrongSignatureError()
+		if isPendingTest(method.Name) {
+			tests.Regular = append(tests.Regular, suiteTest[Suite, T]{
+				Name: method.Name,
+				Info: plugin.RegularTestInfo{
+					RawBaseName: method.Name,
+					Level:       1,
+					Pending:     true,
+				},
+				Run:  func(Suite, T) {},
+				Skip: "pending",
+			})
+
+			continue
 		}
 
-		if method.Type.In(1) != reflect.TypeFor[T]() {
-			raiseWrongSignatureError()
+		for _, typ := range args.Fixtures {
+			requiredFixtures[typ] = struct{}{}
 		}
 
-		if method.Type.NumIn() == 3 && method.Type.In(2).Kind() != reflect.Struct {
-			raiseWrongSignatureError()
+		if isProperty {
+			if args.Params == -1 {
+				raiseWrongSignatureError()
+			}
+
+			tests.Regular = append(
+				tests.Regular,
+				newPropertyTest(t, method, args, gens, fixtures, timeouts[method.Name]),
+			)
+
+			continue
 		}
 
-		switch method.Type.NumIn() {
-		default:
-			raiseWrongSignatureError()
+		strategy, hasStrategy := strategies[method.Name]
+
+		if args.Params == -1 {
+			if hasStrategy {
+				t.Fatalf(
+					"wrong strategies for %[1]s: test %[2]s is not parametrized",
+					vt, method.Name,
+				)
+			}
 
-		case 2: // regular test - (Suite, T)
-			//nolint:forcetypeassert // checked by reflection
 			tests.Regular = append(tests.Regular, suiteTest[Suite, T]{
 				Name: method.Name,
 				Info: plugin.RegularTestInfo{
 					RawBaseName: method.Name,
 					Level:       1,
 				},
-				Run: method.Func.Interface().(func(Suite, T)),
+				Run: func(s Suite, t T) {
+					callTest(method, args, s, t, reflect.Value{}, fixtures)
+				},
+				Timeout: timeouts[method.Name],
 			})
 
-		case 3: // parametrized test - (Suite, T, Params)
-			param := method.Type.In(2)
+			continue
+		}
 
-			requiredCases := make(map[string]suiteCase[Suite])
+		param := method.Type.In(args.Params)
 
-			for i := range param.NumField() {
-				field := param.Field(i)
+		requiredCases := requiredCasesOf(t, method.Name, param, cases)
 
-				c, ok := cases[field.Name]
-				if !ok {
-					t.Fatalf(
-						"wrong param signature for %[1]s.%[2]s: Cases%[3]s for param %[3]q not found",
-						reflect.TypeFor[Suite](),
-						method.Name,
-						field.Name,
-					)
-				}
+		if !hasStrategy {
+			strategy = Cartesian()
+		}
 
-				if !c.Provides.AssignableTo(field.Type) {
-					// TODO: "of type ..." shows invalid type
-					t.Fatalf(
-						"wrong param signature for %[1]s.%[2]s: Cases%[3]s provides %s values, not assignable to param %[3]q of type %s",
-						reflect.TypeFor[Suite](),
-						method.Name,
-						field.Name,
-						c.Provides,
-						field.Type,
-					)
-				}
+		strategy.validate(t, vt.String()+"."+method.Name, param)
 
-				requiredCases[field.Name] = c
-			}
+		tests.Parametrized = append(
+			tests.Parametrized,
+			newParametrizedTest(
+				method.Name,
+				param,
+				requiredCases,
+				strategy,
+				timeouts[method.Name],
+				func(s Suite, t T, params reflect.Value) {
+					callTest(method, args, s, t, params, fixtures)
+				},
+			),
+		)
+	}
 
-			tests.Parametrized = append(
-				tests.Parametrized,
-				newParametrizedTest[Suite, T](method.Name, method, requiredCases),
-			)
+	for _, typ := range fixtures.sortedTypes() {
+		if _, ok := requiredFixtures[typ]; ok {
+			tests.Fixtures = append(tests.Fixtures, typ)
 		}
 	}
 
 	return tests
 }
 
-func newParametrizedTest[Suite any, T CommonT](
-	name string,
+// focusedTestsOf returns names of the focused suite tests.
+//
+// It fails if there are any and -testo.fail-on-focus flag is set.
+func focusedTestsOf[Suite any, T CommonT](t T) []string {
+	vt := reflect.TypeFor[Suite]()
+
+	var focused []string
+
+	for i := range vt.NumMethod() {
+		if name := vt.Method(i).Name; isFocusedTest(name) {
+			focused = append(focused, name)
+		}
+	}
+
+	if len(focused) == 0 {
+		return nil
+	}
+
+	if *failOnFocusFlag {
+		t.Fatalf(
+			"focused tests found in %[1]s: %[2]s, remove the focus before committing",
+			vt, strings.Join(focused, ", "),
+		)
+
+		return nil
+	}
+
+	t.Logf("running only focused tests of %s: %s", vt, strings.Join(focused, ", "))
+
+	return focused
+}
+
+// testArgsOf returns arguments of the test method:
+//
+//	func (Suite) TestFoo(t T, [params struct{...}], [fixtures...])
+//
+// It reports false if the method has wrong signature.
+// If that is because no fixture provides some parameter, its type is returned as missing.
+func testArgsOf[Suite any, T CommonT](
+	method reflect.Method,
+	fixtures suiteFixtures[Suite, T],
+) (args testArgs, missing reflect.Type, ok bool) {
+	if method.Type.NumOut() != 0 ||
+		method.Type.NumIn() < 2 ||
+		method.Type.In(1) != reflect.TypeFor[T]() {
+		return testArgs{}, nil, false
+	}
+
+	args = testArgs{
+		Params:   -1,
+		Fixtures: make(map[int]reflect.Type),
+	}
+
+	for i := 2; i < method.Type.NumIn(); i++ {
+		in := method.Type.In(i)
+
+		if _, ok := fixtures[in]; ok {
+			args.Fixtures[i] = in
+
+			continue
+		}
+
+		// only the first struct is params, others must be fixtures
+		if in.Kind() != reflect.Struct || args.Params != -1 {
+			return testArgs{}, in, false
+		}
+
+		args.Params = i
+	}
+
+	return args, nil, true
+}
+
+// callTest calls the test method with the given params and fixtures resolved for t.
+func callTest[Suite any, T CommonT](
 	method reflect.Method,
+	args testArgs,
+	s Suite,
+	t T,
+	params reflect.Value,
+	fixtures suiteFixtures[Suite, T],
+) {
+	t.Helper()
+
+	in := make([]reflect.Value, method.Type.NumIn())
+
+	in[0] = reflect.ValueOf(s)
+	in[1] = reflect.ValueOf(t)
+
+	if args.Params != -1 {
+		in[args.Params] = params
+	}
+
+	// resolve in order of arguments so that cleanups are determenistic
+	for i := 2; i < len(in); i++ {
+		if typ, ok := args.Fixtures[i]; ok {
+			in[i] = fixtures.Resolve(t, s, typ)
+		}
+	}
+
+	method.Func.Call(in)
+}
+
+// requiredCasesOf returns cases for the fields of the params struct
+// of the given test method.
+func requiredCasesOf[Suite any](
+	t fataller,
+	name string,
+	param reflect.Type,
 	cases map[string]suiteCase[Suite],
-) func(Suite) []suiteTest[Suite, T] {
-	param := method.Type.In(2)
+) map[string]suiteCase[Suite] {
+	requiredCases := make(map[string]suiteCase[Suite])
+
+	for i := range param.NumField() {
+		field := param.Field(i)
+
+		c, ok := cases[field.Name]
+		if !ok {
+			t.Fatalf(
+				"wrong param signature for %[1]s.%[2]s: Cases%[3]s for param %[3]q not found",
+				reflect.TypeFor[Suite](),
+				name,
+				field.Name,
+			)
+		}
+
+		c = c.resolve(t, name, field.Name, field.Type)
+
+		if !c.Provides.AssignableTo(field.Type) {
+			// TODO: "of type ..." shows invalid type
+			t.Fatalf(
+				"wrong param signature for %[1]s.%[2]s: Cases%[3]s provides %s values, not assignable to param %[3]q of type %s",
+				reflect.TypeFor[Suite](),
+				name,
+				field.Name,
+				c.Provides,
+				field.Type,
+			)
+		}
+
+		requiredCases[field.Name] = c
+	}
+
+	return requiredCases
+}
 
+// newParametrizedTest returns a func which creates a test for each combination of cases.
+//
+// Given call runs the test with the params struct value.
+func newParametrizedTest[Suite any, T any](
+	name string,
+	param reflect.Type,
+	cases map[string]suiteCase[Suite],
+	strategy Strategy,
+	timeout time.Duration,
+	call func(s Suite, t T, params reflect.Value),
+) func(Suite) []suiteTest[Suite, T] {
 	return func(s Suite) []suiteTest[Suite, T] {
-		casesValues := make(map[string][]reflect.Value, len(cases))
+		casesValues := make(map[string][]suiteCaseValue, len(cases))
 
 		for name, c := range cases {
 			casesValues[name] = c.Func(s)
 		}
 
+		// case names follow the order of params fields
+		order := make([]string, 0, len(cases))
+
+		for i := range param.NumField() {
+			name := param.Field(i).Name
+
+			if _, ok := cases[name]; ok {
+				order = append(order, name)
+			}
+		}
+
 		var (
 			tests []suiteTest[Suite, T]
 			i     int
+
+			// names of the cases so far, to keep them unique
+			caseNames = make(map[string]bool)
 		)
 
-		for _, params := range casesPermutations(casesValues) {
+		for _, params := range strategy.combine(casesValues) {
 			i++
 
 			paramValue := reflect.New(param).Elem()
@@ -338,25 +747,38 @@ func newParametrizedTest[Suite any, T CommonT](
 			caseParams := make(map[string]any, len(params))
 
 			for name, value := range params {
-				paramValue.FieldByName(name).Set(value)
+				paramValue.FieldByName(name).Set(value.Value)
+
+				caseParams[name] = value.Value.Interface()
+			}
+
+			caseName, skip, tags := describeCase(params, order)
+
+			if caseName != "" {
+				caseName = uniqueCaseName(caseNames, caseName)
+			}
 
-				caseParams[name] = value.Interface()
+			// Cases without names are numbered,
+			// since their values may not be representable as a name.
+			testName := fmt.Sprintf("%s case %d", name, i)
+			if caseName != "" {
+				testName = name + "/" + caseName
 			}
 
 			tests = append(tests, suiteTest[Suite, T]{
-				// TODO: better name? Allow plugins customize it?
-				Name: fmt.Sprintf("%s case %d", name, i),
+				Name: testName,
 				Info: plugin.ParametrizedTestInfo{
 					RawBaseName: name,
+					CaseName:    caseName,
 					Params:      caseParams,
+					Tags:        tags,
+					Strategy:    strategy.String(),
 				},
 				Run: func(s Suite, t T) {
-					method.Func.Call([]reflect.Value{
-						reflect.ValueOf(s),
-						reflect.ValueOf(t),
-						paramValue,
-					})
+					call(s, t, paramValue)
 				},
+				Timeout: timeout,
+				Skip:    skip,
 			})
 		}
 
@@ -364,6 +786,72 @@ func newParametrizedTest[Suite any, T CommonT](
 	}
 }
 
+// describeCase returns name, skip reason and tags of the given
+// combination of case values.
+//
+// Name is empty if none of the values are named.
+// Otherwise, it is a single segment of the test name:
+// the value names, or their indices if unnamed, joined with underscore
+// in the given order of params, with slashes in names replaced by underscores.
+// Such names may collide, see [uniqueCaseName].
+func describeCase(params map[string]suiteCaseValue, order []string) (name, skip string, tags []string) {
+	var (
+		segments []string
+		skips    []string
+		isNamed  bool
+	)
+
+	for _, key := range order {
+		value, ok := params[key]
+		if !ok {
+			continue
+		}
+
+		if value.Name != "" {
+			isNamed = true
+
+			// each case is a single segment of the test name
+			segments = append(segments, strings.ReplaceAll(value.Name, "/", "_"))
+		} else {
+			segments = append(segments, strconv.Itoa(value.Index))
+		}
+
+		if value.Skip != "" {
+			skips = append(skips, value.Skip)
+		}
+
+		for _, tag := range value.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	if isNamed {
+		name = strings.Join(segments, "_")
+	}
+
+	return name, strings.Join(skips, "; "), tags
+}
+
+// uniqueCaseName returns the case name made unique among the seen ones
+// by appending _N suffix, where N is the number of the case with this name,
+// and adds it to them.
+//
+// Names collide when names of values contain underscores,
+// e.g. "a_b" and "c" with "a" and "b_c", or when a value is named after an index.
+func uniqueCaseName(seen map[string]bool, name string) string {
+	unique := name
+
+	for n := 2; seen[unique]; n++ {
+		unique = fmt.Sprintf("%s_%d", name, n)
+	}
+
+	seen[unique] = true
+
+	return unique
+}
+
 // casesPermutations returns a determenistic permutations of the given cases values for test.
 func casesPermutations[V any](v map[string][]V) []map[string]V {
 	var result []map[string]V
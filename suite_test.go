@@ -1,10 +1,21 @@
 package testo
 
 import (
+	"flag"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/metafates/testo/plugin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ---- Tests for suiteCasesOf ----
@@ -35,7 +46,7 @@ func TestSuiteCasesOf(t *testing.T) {
 	ints := make([]int, len(numVals))
 
 	for i, rv := range numVals {
-		ints[i] = int(rv.Int())
+		ints[i] = int(rv.Value.Int())
 	}
 
 	assert.Equal(t, []int{1, 2, 3}, ints, "Numbers case values should match")
@@ -123,3 +134,503 @@ func TestCloneSuite_DeepClone(t *testing.T) {
 	// clone.Value should remain the initial value
 	assert.Equal(t, 5, clone.Value)
 }
+
+// ---- Tests for testArgsOf ----
+
+type ArgsSuite struct{}
+
+func (ArgsSuite) TestMissing(*T, struct{}, *os.File) {}
+
+func TestTestArgsOf_MissingFixture(t *testing.T) {
+	method, _ := reflect.TypeFor[ArgsSuite]().MethodByName("TestMissing")
+
+	_, missing, ok := testArgsOf(method, suiteFixturesOf[ArgsSuite, *T](t))
+
+	assert.False(t, ok)
+	assert.Equal(t, reflect.TypeFor[*os.File](), missing)
+}
+
+// ---- Tests for named cases ----
+
+type NamedCasesSuite struct{}
+
+func (NamedCasesSuite) CasesInput() []Case[string] {
+	return []Case[string]{
+		{Name: "empty input", Value: ""},
+		{Name: "unicode", Value: "привет", Tags: []string{"i18n"}, Skip: "not supported"},
+	}
+}
+
+func (NamedCasesSuite) CasesMode() map[string]int {
+	return map[string]int{"fast": 1, "slow": 2}
+}
+
+func (NamedCasesSuite) CasesCount() []int {
+	return []int{10}
+}
+
+func TestSuiteCasesOf_Named(t *testing.T) {
+	cases := suiteCasesOf[NamedCasesSuite](t)
+
+	assert.Equal(t, reflect.TypeFor[string](), cases["Input"].Provides)
+	assert.Equal(t, reflect.TypeFor[int](), cases["Mode"].Provides)
+
+	values := map[string][]suiteCaseValue{
+		"Input": cases["Input"].Func(NamedCasesSuite{}),
+		"Mode":  cases["Mode"].Func(NamedCasesSuite{}),
+		"Count": cases["Count"].Func(NamedCasesSuite{}),
+	}
+
+	assert.Equal(t, "slow", values["Mode"][1].Name)
+	assert.Equal(t, 2, values["Mode"][1].Value.Interface())
+
+	var names, skips []string
+
+	for _, params := range casesPermutations(values) {
+		name, skip, _ := describeCase(params, []string{"Input", "Mode", "Count"})
+
+		names = append(names, name)
+		skips = append(skips, skip)
+	}
+
+	assert.Equal(t, []string{
+		"empty input_fast_1",
+		"empty input_slow_1",
+		"unicode_fast_1",
+		"unicode_slow_1",
+	}, names)
+	assert.Equal(t, []string{"", "", "not supported", "not supported"}, skips)
+}
+
+func TestDescribeCase_Unnamed(t *testing.T) {
+	name, skip, tags := describeCase(map[string]suiteCaseValue{
+		"X": {Value: reflect.ValueOf(1), Index: 1},
+	}, []string{"X"})
+
+	assert.Empty(t, name)
+	assert.Empty(t, skip)
+	assert.Empty(t, tags)
+}
+
+func TestUniqueCaseName(t *testing.T) {
+	seen := make(map[string]bool)
+
+	assert.Equal(t, "a_b_c", uniqueCaseName(seen, "a_b_c"))
+	assert.Equal(t, "a_b_c_2", uniqueCaseName(seen, "a_b_c"))
+	assert.Equal(t, "a_b_c_3", uniqueCaseName(seen, "a_b_c"))
+	assert.Equal(t, "a_b_c_2_2", uniqueCaseName(seen, "a_b_c_2"))
+}
+
+// ---- Tests for strategies ----
+
+func TestCasesZip(t *testing.T) {
+	combinations := casesZip(map[string][]int{
+		"A": {1, 2, 3},
+		"B": {10, 20},
+	})
+
+	assert.Equal(t, []map[string]int{
+		{"A": 1, "B": 10},
+		{"A": 2, "B": 20},
+	}, combinations)
+}
+
+func TestCasesNWise(t *testing.T) {
+	values := map[string][]int{
+		"A": {0, 1, 2},
+		"B": {0, 1, 2},
+		"C": {0, 1, 2},
+		"D": {0, 1, 2},
+	}
+
+	combinations := casesNWise(values, 2, nil)
+
+	assert.Less(t, len(combinations), len(casesPermutations(values)))
+
+	keys := []string{"A", "B", "C", "D"}
+
+	for i, a := range keys {
+		for _, b := range keys[i+1:] {
+			for _, va := range values[a] {
+				for _, vb := range values[b] {
+					assert.True(t, slices.ContainsFunc(combinations, func(c map[string]int) bool {
+						return c[a] == va && c[b] == vb
+					}), "pair %s=%d %s=%d is not covered", a, va, b, vb)
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, combinations, casesNWise(values, 2, nil), "must be determenistic")
+	assert.Len(t, casesNWise(values, 4, nil), 81, "n-wise for all params is cartesian")
+}
+
+func TestStrategy_Combine(t *testing.T) {
+	valuesOf := func(values ...any) []suiteCaseValue {
+		result := make([]suiteCaseValue, 0, len(values))
+
+		for i, v := range values {
+			result = append(result, suiteCaseValue{Value: reflect.ValueOf(v), Index: i + 1})
+		}
+
+		return result
+	}
+
+	cases := map[string][]suiteCaseValue{
+		"OS":      valuesOf("linux", "windows"),
+		"Browser": valuesOf("chrome", "safari"),
+	}
+
+	strategy := Cartesian().
+		Exclude(map[string]any{"OS": "windows", "Browser": "safari"}).
+		Include(map[string]any{"OS": "darwin", "Browser": "safari"})
+
+	var got []string
+
+	for _, c := range strategy.combine(cases) {
+		got = append(got, c["OS"].Value.String()+"+"+c["Browser"].Value.String())
+	}
+
+	assert.Equal(t, []string{
+		"linux+chrome",
+		"windows+chrome",
+		"linux+safari",
+		"darwin+safari",
+	}, got)
+
+	assert.Equal(t, "cartesian (1 included, 1 excluded)", strategy.String())
+	assert.Equal(t, "pairwise", Pairwise().String())
+	assert.Equal(t, "3-wise", NWise(3).String())
+}
+
+func TestCasesNWise_Exclude(t *testing.T) {
+	values := map[string][]int{
+		"A": {0, 1, 2},
+		"B": {0, 1, 2},
+		"C": {0, 1, 2},
+		"D": {0, 1},
+	}
+
+	// A=0 requires B=0, and C=1 is not allowed with D=1
+	isExcluded := func(c map[string]int) bool {
+		if a, ok := c["A"]; ok {
+			if b, ok := c["B"]; ok && a == 0 && b != 0 {
+				return true
+			}
+		}
+
+		c1, ok1 := c["C"]
+		d1, ok2 := c["D"]
+
+		return ok1 && ok2 && c1 == 1 && d1 == 1
+	}
+
+	combinations := casesNWise(values, 2, isExcluded)
+
+	keys := []string{"A", "B", "C", "D"}
+
+	for _, c := range combinations {
+		assert.False(t, isExcluded(c), "combination %v is excluded", c)
+	}
+
+	for i, a := range keys {
+		for _, b := range keys[i+1:] {
+			for _, va := range values[a] {
+				for _, vb := range values[b] {
+					allowed := slices.ContainsFunc(casesPermutations(values), func(c map[string]int) bool {
+						return c[a] == va && c[b] == vb && !isExcluded(c)
+					})
+
+					covered := slices.ContainsFunc(combinations, func(c map[string]int) bool {
+						return c[a] == va && c[b] == vb
+					})
+
+					assert.Equal(t, allowed, covered, "pair %s=%d %s=%d", a, va, b, vb)
+				}
+			}
+		}
+	}
+}
+
+func TestCasesNWise_ExcludeInfeasible(t *testing.T) {
+	keys := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+
+	values := make(map[string][]int, len(keys))
+
+	for _, key := range keys {
+		values[key] = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	}
+
+	// A=0 is excluded with any H, so no tuple with A=0 can be covered
+	isExcluded := func(c map[string]int) bool {
+		a, ok := c["A"]
+		_, hasH := c["H"]
+
+		return ok && hasH && a == 0
+	}
+
+	done := make(chan []map[string]int)
+
+	go func() {
+		done <- casesNWise(values, 2, isExcluded)
+	}()
+
+	var combinations []map[string]int
+
+	select {
+	case combinations = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("n-wise combinations were not found in time")
+	}
+
+	for _, c := range combinations {
+		assert.False(t, isExcluded(c), "combination %v is excluded", c)
+	}
+
+	for _, b := range keys[1:] {
+		for _, va := range values["A"] {
+			for _, vb := range values[b] {
+				covered := slices.ContainsFunc(combinations, func(c map[string]int) bool {
+					return c["A"] == va && c[b] == vb
+				})
+
+				assert.Equal(t, va != 0, covered, "pair A=%d %s=%d", va, b, vb)
+			}
+		}
+	}
+}
+
+func TestStrategy_CombineIncluded(t *testing.T) {
+	cases := map[string][]suiteCaseValue{
+		"N": {{Value: reflect.ValueOf(1), Index: 1}},
+	}
+
+	strategy := Cartesian().Include(
+		map[string]any{"N": 2},
+		map[string]any{"N": 3},
+		map[string]any{"N": 2},
+		map[string]any{"N": 1},
+	)
+
+	var indices []int
+
+	for _, c := range strategy.combine(cases) {
+		indices = append(indices, c["N"].Index)
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 2, 1}, indices)
+}
+
+func TestGenInt(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for _, bounds := range [][2]int{
+		{0, 0},
+		{-3, 3},
+		{math.MinInt, math.MaxInt},
+		{math.MaxInt - 1, math.MaxInt},
+	} {
+		g := GenInt(bounds[0], bounds[1])
+
+		for range 100 {
+			v := g.Generate(r)
+
+			assert.GreaterOrEqual(t, v, bounds[0])
+			assert.LessOrEqual(t, v, bounds[1])
+		}
+	}
+}
+
+func TestGenSlice_NegativeMaxLen(t *testing.T) {
+	assert.PanicsWithValue(t, "testo: GenSlice requires maxLen >= 0", func() {
+		GenSlice(GenBool(), -1)
+	})
+
+	assert.PanicsWithValue(t, "testo: GenSlice requires maxLen >= 0", func() {
+		GenString(-1)
+	})
+}
+
+func TestCaseFile_Errors(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	tests := []struct {
+		file    string
+		content string
+		want    string
+	}{
+		{
+			file:    "syntax.json",
+			content: "[\n  {\"name\": \"Alice\"},\n  {\"name\": }\n]",
+			want:    "syntax.json:3: invalid character '}' after array element",
+		},
+		{
+			file:    "type.json",
+			content: "{\n  \"alice\": {\"name\": \"Alice\"},\n  \"bob\": {\"age\": \"old\"}\n}",
+			want:    "type.json:3: row bob: json: cannot unmarshal string into Go struct field user.age of type int",
+		},
+		{
+			file:    "type.csv",
+			content: "name,age\nAlice,30\nBob,old\n",
+			want:    `type.csv:3: row 2: column "age": strconv.ParseInt: parsing "old": invalid syntax`,
+		},
+		{
+			file:    "column.csv",
+			content: "name,email\nAlice,alice@example.com\n",
+			want:    `column.csv:2: row 1: column "email" does not match any field of testo.user`,
+		},
+		{
+			file:    "users.txt",
+			content: "Alice",
+			want:    `users.txt: unsupported format ".txt", must be one of .csv, .json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			file, err := loadCaseFile(path)
+			if err == nil {
+				_, err = file.values(reflect.TypeFor[user]())
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, filepath.Join(filepath.Dir(path), tt.want), err.Error())
+		})
+	}
+}
+
+func TestIsFlagSet(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	set.Int("retries", 1, "")
+	set.Int("other", 1, "")
+
+	require.NoError(t, set.Parse([]string{"-retries=0"}))
+
+	assert.True(t, isFlagSet(set, "retries"))
+	assert.False(t, isFlagSet(set, "other"))
+}
+
+func TestParseShard(t *testing.T) {
+	s, err := parseShard("2/8")
+	require.NoError(t, err)
+	assert.Equal(t, shard{Index: 2, Total: 8}, s)
+
+	for _, invalid := range []string{"", "2", "0/8", "9/8", "a/8", "1/b", "1/0"} {
+		_, err := parseShard(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestDependencyGroups(t *testing.T) {
+	groups := dependencyGroups(map[string][]string{
+		"TestD": {"TestC"},
+		"TestC": {"TestB"},
+		"TestF": {"TestE"},
+		"TestB": {"TestA"},
+	})
+
+	assert.Equal(t, map[string]string{
+		"TestA": "TestA",
+		"TestB": "TestA",
+		"TestC": "TestA",
+		"TestD": "TestA",
+		"TestE": "TestE",
+		"TestF": "TestE",
+	}, groups)
+}
+
+func TestRegisterCaseFileFormat(t *testing.T) {
+	t.Cleanup(func() {
+		caseFileFormatsMutex.Lock()
+		defer caseFileFormatsMutex.Unlock()
+
+		delete(caseFileFormats, ".txt")
+	})
+
+	RegisterCaseFileFormat(func(data []byte) ([]CaseFileRow, error) {
+		var rows []CaseFileRow
+
+		for i, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			rows = append(rows, CaseFileRow{
+				Key:  line,
+				Line: i + 1,
+				Decode: func(v any) error {
+					*v.(*string) = line //nolint:forcetypeassert // test decodes strings only
+
+					return nil
+				},
+			})
+		}
+
+		return rows, nil
+	}, ".txt")
+
+	path := filepath.Join(t.TempDir(), "names.txt")
+
+	require.NoError(t, os.WriteFile(path, []byte("alice\nbob\n"), 0o600))
+
+	file, err := loadCaseFile(path)
+	require.NoError(t, err)
+
+	values, err := file.values(reflect.TypeFor[string]())
+	require.NoError(t, err)
+
+	var names []string
+
+	for _, v := range values {
+		names = append(names, v.Name+" "+v.Value.String())
+	}
+
+	assert.Equal(t, []string{"names_alice alice", "names_bob bob"}, names)
+}
+
+// ---- Tests for eventStream ----
+
+func TestEventStream_ObserverExits(t *testing.T) {
+	var (
+		stream *eventStream
+		got    []string
+	)
+
+	stream = newEventStream(func(event plugin.Event) {
+		switch e := event.(type) {
+		case plugin.Log:
+			got = append(got, e.Message)
+
+			if e.Message == "fail now" {
+				stream.emit(plugin.Log{Message: "queued"})
+				stream.emit(plugin.SuiteFinished{})
+
+				// as t.FailNow does
+				runtime.Goexit()
+			}
+
+		case plugin.SuiteFinished:
+			got = append(got, "suite finished")
+		}
+	})
+
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+
+		stream.emit(plugin.Log{Message: "fail now"})
+	}()
+
+	<-exited
+
+	assert.Equal(t, []string{"fail now", "queued", "suite finished"}, got)
+
+	stream.emit(plugin.Log{Message: "after"})
+
+	assert.Equal(t, []string{"fail now", "queued", "suite finished", "after"}, got)
+}
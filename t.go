@@ -1,8 +1,14 @@
 package testo
 
 import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -48,6 +54,97 @@ type (
 
 		// info information required for [Inspect].
 		info plugin.TInfo
+
+		// fixtures resolved for this T.
+		fixtures map[reflect.Type]reflect.Value
+
+		// captureFailures states that failures must not be reported to [testing.T].
+		// Instead, they are recorded in info and logged.
+		//
+		// It is used to run test attempts which could be retried.
+		captureFailures bool
+
+		// probing states that the test is run to check an input of property test.
+		// Logs are discarded along with captured failures,
+		// and skipping the test discards the input instead.
+		probing bool
+
+		// discarded states that the input was discarded while probing.
+		discarded bool
+
+		// parallel states whether [testing.T.Parallel] was called.
+		// Attempts of the same test share this value.
+		parallel bool
+
+		// sequential states that [T.Parallel] is no-op for this test,
+		// since other tests depend on it, see [dependencyOrder].
+		sequential bool
+
+		ctx     context.Context
+		ctxOnce sync.Once
+
+		// timer fires when the test timeout expires, if any.
+		timer *time.Timer
+
+		// deadline is the time when the timer fires.
+		deadline time.Time
+
+		// stage describes what is currently running, e.g. "BeforeEach hook".
+		stage atomic.Pointer[string]
+
+		// active is the currently running non-parallel subtest.
+		active atomic.Pointer[T]
+
+		// abandoned states that the test has timed out
+		// and nobody waits for it anymore.
+		abandoned atomic.Bool
+
+		// group runs subtests of the suite test, if any.
+		group         *subtestGroup
+		groupMu       sync.Mutex
+		isGroupClosed bool
+
+		// isGrouped states that this subtest is run in the [subtestGroup].
+		isGrouped bool
+
+		// paused is closed when the grouped subtest calls [T.Parallel],
+		// so that its parent continues, see [T.runGrouped].
+		paused chan struct{}
+
+		// isEnvSet states whether [T.Setenv] was called.
+		// Grouped subtests are not parallel for [testing.T], so we check it ourselves.
+		isEnvSet bool
+
+		// subtestNames names subtests of the suite test attempt, if it could be retried.
+		subtestNames *subtestNames
+
+		// started is the time when this T was created.
+		started time.Time
+
+		// events of the suite run, shared by all its tests.
+		events *eventStream
+
+		// id of this T in the events.
+		id int
+
+		// messages reported by failures and skips of this T, see [T.result].
+		messagesMu      sync.Mutex
+		failureMessages []string
+		skipMessage     string
+
+		// lifetime replaces [testing.T] cleanups, temporary directories
+		// and context if set, see [lifetime].
+		lifetime *lifetime
+
+		// detached, if set, receives logs and failures of this T
+		// in place of the underlying [testing.T], which may finish before this T.
+		// See [T.detach].
+		detached atomic.Pointer[reporter]
+
+		// testName is the name of this T if it differs from the underlying [testing.T] one,
+		// e.g. for the fuzzed suite, which is set up by its first input,
+		// or for subtests of the retried attempt, see [subtestNames].
+		testName string
 	}
 
 	actualT = T
@@ -100,42 +197,115 @@ func (t *T) SuiteName() string {
 // -test.count or -test.cpu, multiple instances of a single test never run in
 // parallel with each other.
 //
-// Note that running this method in the second level is not supported and treated as no op.
-//
-//	func TestFoo(t *T) {
-//	   t.Parallel() // level 1, this is ok
-//
-//	   t.Run("...", func(t *T) {
-//			// level 2, this is not supported
-//			t.Parallel()
-//
-//	  		t.Run("...", func(t *T) {
-//				// level 3, supported
-//				t.Parallel()
-//			})
-//		})
-//	}
+// Parallel subtests of the suite test are run before its AfterEach hook.
+// Running this method in subtests started by AfterEach hook
+// is not supported and treated as no op.
 func (t *T) Parallel() {
 	t.Helper()
 
-	// This restricts the pattern seen in the example above.
-	//
-	// The reason for that is that we won't be able to run AfterEach hook otherwise,
-	// because test function will return control flow and continue running in a
-	// separate goroutine later, thus triggering AfterEach too early.
+	// inputs of property tests are checked one by one
+	if t.probing {
+		return
+	}
+
+	if t.sequential {
+		t.Log("WARN: running Parallel() in tests which other tests depend on is not supported and treated as no-op")
+
+		return
+	}
+
+	// Parallel subtests are run after the parent test function returns,
+	// and the suite test function returns only after AfterEach hook.
+	// Subtests started before AfterEach are run in the subtest group,
+	// which waits for them right before it. Others can't be run in parallel.
 	//
 	// We could t.Cleanup(AfterEach) to solve this, but if
 	// AfterEach would call t.Run (which is common enough) the whole test panics,
 	// because running t.Run inside cleanup is not permitted
 	// (which makes sense, but unfortunate in our case).
-	if t.level() == 2 {
+	if t.level() == 2 && !t.isGrouped {
 		// TODO: add link to documentation or something so that user won't be left with questions.
-		t.Log("WARN: running Parallel() at this level is not supported and treated as no-op")
+		t.Log("WARN: running Parallel() in subtests of AfterEach is not supported and treated as no-op")
 
 		return
 	}
 
-	t.plugin.Overrides.Parallel.Call(t.T.Parallel)()
+	t.plugin.Overrides.Parallel.Call(t.setParallel)()
+}
+
+//nolint:funcorder // close to public function for better readability
+func (t *T) setParallel() {
+	t.Helper()
+
+	// Calling parallel twice panics.
+	// It may happen when the test is retried.
+	if t.parallel {
+		return
+	}
+
+	t.parallel = true
+
+	// time spent waiting for other tests does not count
+	remaining, ok := t.pauseTimeout()
+
+	if t.isGrouped {
+		if t.isEnvSet {
+			panic("testing: t.Parallel called after t.Setenv; cannot set environment variables in parallel tests")
+		}
+
+		t.pauseGrouped()
+	} else {
+		t.T.Parallel()
+	}
+
+	if ok {
+		t.resumeTimeout(remaining)
+	}
+}
+
+// Context returns a context that is canceled when the test times out
+// (see [WithTimeout]) or just before Cleanup-registered functions are called.
+//
+// Cleanup functions can wait for any resources
+// that shut down on Context.Done before the test completes.
+func (t *T) Context() context.Context {
+	t.ctxOnce.Do(func() {
+		if t.lifetime != nil {
+			t.ctx = t.lifetime.ctx
+
+			return
+		}
+
+		ctx, cancel := context.WithCancelCause(t.parentContext())
+
+		t.ctx = ctx
+		t.T.Cleanup(func() { cancel(context.Canceled) })
+	})
+
+	return t.ctx
+}
+
+// Cleanup registers a function to be called when the test (or subtest) and all its
+// subtests complete. Cleanup functions will be called in last added,
+// first called order.
+//
+// When called from a fixture provider, cleanup is bound to the fixture [Scope] instead.
+func (t *T) Cleanup(f func()) {
+	t.Helper()
+
+	if t.isAbandoned() {
+		return
+	}
+
+	f = t.observeCleanup(f)
+
+	if t.lifetime != nil {
+		t.lifetime.Cleanup(f)
+
+		return
+	}
+
+	t.T.Cleanup(f)
 }
 
 // Setenv calls os.Setenv(key, value) and uses Cleanup to
@@ -147,6 +317,14 @@ func (t *T) Parallel() {
 func (t *T) Setenv(key, value string) {
 	t.Helper()
 
+	for p := t; p != nil; p = p.parent {
+		if p.isGrouped && p.parallel {
+			panic("testing: t.Setenv called after t.Parallel; cannot set environment variables in parallel tests")
+		}
+	}
+
+	t.isEnvSet = true
+
 	t.plugin.Overrides.Setenv.Call(t.T.Setenv)(key, value)
 }
 
@@ -158,7 +336,23 @@ func (t *T) Setenv(key, value string) {
 func (t *T) TempDir() string {
 	t.Helper()
 
-	return t.plugin.Overrides.TempDir.Call(t.T.TempDir)()
+	return t.plugin.Overrides.TempDir.Call(t.tempDir)()
+}
+
+//nolint:funcorder // close to public function for better readability
+func (t *T) tempDir() string {
+	t.Helper()
+
+	if t.lifetime == nil {
+		return t.T.TempDir()
+	}
+
+	dir, err := t.lifetime.TempDir()
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+
+	return dir
 }
 
 // Log formats its arguments using default formatting, analogous to Println,
@@ -168,7 +362,25 @@ func (t *T) TempDir() string {
 func (t *T) Log(args ...any) {
 	t.Helper()
 
-	t.plugin.Overrides.Log.Call(t.T.Log)(args...)
+	// reported before overrides, which may not call the original Log
+	t.addLog(sprintln(args...))
+
+	t.plugin.Overrides.Log.Call(t.log)(args...)
+}
+
+//nolint:funcorder // close to public function for better readability
+func (t *T) log(args ...any) {
+	t.Helper()
+
+	if t.isAbandoned() || t.probing {
+		return
+	}
+
+	if t.reportDetached(sprintln(args...), false) {
+		return
+	}
+
+	t.T.Log(args...)
 }
 
 // Logf formats its arguments according to the format, analogous to Printf, and
@@ -179,17 +391,53 @@ func (t *T) Log(args ...any) {
 func (t *T) Logf(format string, args ...any) {
 	t.Helper()
 
-	t.plugin.Overrides.Logf.Call(t.T.Logf)(format, args...)
+	// reported before overrides, which may not call the original Logf
+	t.addLog(fmt.Sprintf(format, args...))
+
+	t.plugin.Overrides.Logf.Call(t.logf)(format, args...)
+}
+
+//nolint:funcorder // close to public function for better readability
+func (t *T) logf(format string, args ...any) {
+	t.Helper()
+
+	if t.isAbandoned() || t.probing {
+		return
+	}
+
+	if t.reportDetached(fmt.Sprintf(format, args...), false) {
+		return
+	}
+
+	t.T.Logf(format, args...)
 }
 
 // Deadline reports the time at which the test binary will have
-// exceeded the timeout specified by the -timeout flag.
+// exceeded the timeout specified by the -timeout flag
+// or the test will have exceeded the timeout set by [WithTimeout], whichever is earlier.
 //
-// The ok result is false if the -timeout flag indicates “no timeout” (0).
+// The ok result is false if there is no timeout.
 func (t *T) Deadline() (time.Time, bool) {
 	t.Helper()
 
-	return t.plugin.Overrides.Deadline.Call(t.T.Deadline)()
+	return t.plugin.Overrides.Deadline.Call(t.testDeadline)()
+}
+
+//nolint:funcorder // close to public function for better readability
+func (t *T) testDeadline() (time.Time, bool) {
+	deadline, ok := t.T.Deadline()
+
+	for t := t; t != nil; t = t.parent {
+		if t.timer == nil {
+			continue
+		}
+
+		if !ok || t.deadline.Before(deadline) {
+			deadline, ok = t.deadline, true
+		}
+	}
+
+	return deadline, ok
 }
 
 // Errorf is equivalent to Logf followed by Fail.
@@ -203,7 +451,25 @@ func (t *T) Errorf(format string, args ...any) {
 func (t *T) errorf(format string, args ...any) {
 	t.Helper()
 
+	if t.isAbandoned() {
+		return
+	}
+
 	t.info.FailureKind = plugin.TestFailureKindSoft
+	t.addFailure(fmt.Sprintf(format, args...))
+
+	if t.reportDetached(fmt.Sprintf(format, args...), true) {
+		return
+	}
+
+	if t.captureFailures {
+		if !t.probing {
+			t.T.Logf(format, args...)
+		}
+
+		return
+	}
+
 	t.T.Errorf(format, args...)
 }
 
@@ -218,7 +484,25 @@ func (t *T) Error(args ...any) {
 func (t *T) error(args ...any) {
 	t.Helper()
 
+	if t.isAbandoned() {
+		return
+	}
+
 	t.info.FailureKind = plugin.TestFailureKindSoft
+	t.addFailure(sprintln(args...))
+
+	if t.reportDetached(sprintln(args...), true) {
+		return
+	}
+
+	if t.captureFailures {
+		if !t.probing {
+			t.T.Log(args...)
+		}
+
+		return
+	}
+
 	t.T.Error(args...)
 }
 
@@ -226,7 +510,25 @@ func (t *T) error(args ...any) {
 func (t *T) Skip(args ...any) {
 	t.Helper()
 
-	t.plugin.Overrides.Skip.Call(t.T.Skip)(args...)
+	t.plugin.Overrides.Skip.Call(t.skip)(args...)
+}
+
+//nolint:funcorder // close to public function for better readability
+func (t *T) skip(args ...any) {
+	t.Helper()
+
+	if t.isAbandoned() {
+		runtime.Goexit()
+	}
+
+	if t.probing {
+		t.discarded = true
+
+		runtime.Goexit()
+	}
+
+	t.addSkip(sprintln(args...))
+	t.T.Skip(args...)
 }
 
 // SkipNow marks the test as having been skipped and stops its execution
@@ -240,14 +542,50 @@ func (t *T) Skip(args ...any) {
 func (t *T) SkipNow() {
 	t.Helper()
 
-	t.plugin.Overrides.SkipNow.Call(t.T.SkipNow)()
+	t.plugin.Overrides.SkipNow.Call(t.skipNow)()
+}
+
+//nolint:funcorder // close to public function for better readability
+func (t *T) skipNow() {
+	t.Helper()
+
+	if t.isAbandoned() {
+		runtime.Goexit()
+	}
+
+	if t.probing {
+		t.discarded = true
+
+		runtime.Goexit()
+	}
+
+	t.addSkip("")
+	t.T.SkipNow()
 }
 
 // Skipf is equivalent to Logf followed by SkipNow.
 func (t *T) Skipf(format string, args ...any) {
 	t.Helper()
 
-	t.plugin.Overrides.Skipf.Call(t.T.Skipf)(format, args...)
+	t.plugin.Overrides.Skipf.Call(t.skipf)(format, args...)
+}
+
+//nolint:funcorder // close to public function for better readability
+func (t *T) skipf(format string, args ...any) {
+	t.Helper()
+
+	if t.isAbandoned() {
+		runtime.Goexit()
+	}
+
+	if t.probing {
+		t.discarded = true
+
+		runtime.Goexit()
+	}
+
+	t.addSkip(fmt.Sprintf(format, args...))
+	t.T.Skipf(format, args...)
 }
 
 // Skipped reports whether the test was skipped.
@@ -268,7 +606,21 @@ func (t *T) Fail() {
 func (t *T) fail() {
 	t.Helper()
 
+	if t.isAbandoned() {
+		return
+	}
+
 	t.info.FailureKind = plugin.TestFailureKindSoft
+	t.addFailure("")
+
+	if t.reportDetached("failed", true) {
+		return
+	}
+
+	if t.captureFailures {
+		return
+	}
+
 	t.T.Fail()
 }
 
@@ -290,7 +642,17 @@ func (t *T) FailNow() {
 func (t *T) failNow() {
 	t.Helper()
 
+	if t.isAbandoned() {
+		runtime.Goexit()
+	}
+
 	t.info.FailureKind = plugin.TestFailureKindFatal
+	t.addFailure("")
+
+	if t.reportDetached("failed", true) || t.captureFailures {
+		runtime.Goexit()
+	}
+
 	t.T.FailNow()
 }
 
@@ -298,7 +660,22 @@ func (t *T) failNow() {
 func (t *T) Failed() bool {
 	t.Helper()
 
-	return t.plugin.Overrides.Failed.Call(t.T.Failed)()
+	return t.plugin.Overrides.Failed.Call(t.failed)()
+}
+
+//nolint:funcorder // close to public function for better readability
+func (t *T) failed() bool {
+	t.Helper()
+
+	if t.detached.Load() != nil {
+		return t.info.FailureKind != plugin.TestFailureKindNone
+	}
+
+	if t.captureFailures && t.info.FailureKind != plugin.TestFailureKindNone {
+		return true
+	}
+
+	return t.T.Failed()
 }
 
 // Fatal is equivalent to Log followed by FailNow.
@@ -312,7 +689,25 @@ func (t *T) Fatal(args ...any) {
 func (t *T) fatal(args ...any) {
 	t.Helper()
 
+	if t.isAbandoned() {
+		runtime.Goexit()
+	}
+
 	t.info.FailureKind = plugin.TestFailureKindFatal
+	t.addFailure(sprintln(args...))
+
+	if t.reportDetached(sprintln(args...), true) {
+		runtime.Goexit()
+	}
+
+	if t.captureFailures {
+		if !t.probing {
+			t.T.Log(args...)
+		}
+
+		runtime.Goexit()
+	}
+
 	t.T.Fatal(args...)
 }
 
@@ -327,7 +722,25 @@ func (t *T) Fatalf(format string, args ...any) {
 func (t *T) fatalf(format string, args ...any) {
 	t.Helper()
 
+	if t.isAbandoned() {
+		runtime.Goexit()
+	}
+
 	t.info.FailureKind = plugin.TestFailureKindFatal
+	t.addFailure(fmt.Sprintf(format, args...))
+
+	if t.reportDetached(fmt.Sprintf(format, args...), true) {
+		runtime.Goexit()
+	}
+
+	if t.captureFailures {
+		if !t.probing {
+			t.T.Logf(format, args...)
+		}
+
+		runtime.Goexit()
+	}
+
 	t.T.Fatalf(format, args...)
 }
 
@@ -342,27 +755,57 @@ func (t *T) Name() string {
 	return t.plugin.Overrides.Name.Call(t.name)()
 }
 
-// name returns test name without [parallelWrapperTest] segment.
+// name returns test name without [parallelWrapperTest] segments.
 func (t *T) name() string {
 	const sep = "/"
 
+	if t.testName != "" {
+		return t.testName
+	}
+
 	name := t.T.Name()
 
 	// segments in test name are always separate by forward slash /
 	segments := strings.Split(name, sep)
 
-	idx := slices.IndexFunc(segments, func(s string) bool {
+	segments = slices.DeleteFunc(segments, func(s string) bool {
 		return s == parallelWrapperTest
 	})
-	if idx == -1 {
-		return name
-	}
 
-	segments = slices.Delete(segments, idx, idx+1)
+	return strings.Join(segments, sep)
+}
 
-	name = strings.Join(segments, sep)
+// reporter receives logs and failures of detached [T].
+//
+// It is implemented by [runFixtureRegistry] and [fuzzReporter].
+type reporter interface {
+	Log(args ...any)
+	Error(args ...any)
+}
 
-	return name
+// detach t from the underlying [testing.T], so that its logs and failures are given to r.
+//
+// It is used for T which outlives the test it was created by,
+// e.g. T given to the provider of [ScopeRun] fixture.
+func (t *T) detach(r reporter) {
+	t.detached.Store(&r)
+}
+
+// reportDetached reports the message of detached T, see [T.detach].
+// It reports whether t is detached.
+func (t *T) reportDetached(msg string, failed bool) bool {
+	r := t.detached.Load()
+	if r == nil {
+		return false
+	}
+
+	if failed {
+		(*r).Error(msg)
+	} else {
+		(*r).Log(msg)
+	}
+
+	return true
 }
 
 // unwrap the underlying T.
@@ -387,6 +830,44 @@ func (t *T) level() int {
 	return level
 }
 
+// fixture returns the fixture value of the given type
+// resolved for this T or any of its parents.
+func (t *T) fixture(typ reflect.Type) (reflect.Value, bool) {
+	for t != nil {
+		if value, ok := t.fixtures[typ]; ok {
+			return value, true
+		}
+
+		t = t.parent
+	}
+
+	return reflect.Value{}, false
+}
+
+func (t *T) setFixture(typ reflect.Type, value reflect.Value) {
+	if t.fixtures == nil {
+		t.fixtures = make(map[reflect.Type]reflect.Value)
+	}
+
+	t.fixtures[typ] = value
+}
+
+func (t *T) parentContext() context.Context {
+	if t.parent == nil {
+		return context.Background()
+	}
+
+	return t.parent.Context()
+}
+
+// setContext sets the context returned by [T.Context].
+// It must be called before any [T.Context] calls.
+func (t *T) setContext(ctx context.Context) {
+	t.ctxOnce.Do(func() {
+		t.ctx = ctx
+	})
+}
+
 func (t *T) options() []plugin.Option {
 	options := t.levelOptions
 
@@ -0,0 +1,138 @@
+package testo
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/metafates/testo/internal/reflectutil"
+	"github.com/metafates/testo/internal/tagexpr"
+	"github.com/metafates/testo/plugin"
+)
+
+// suiteTagsOf returns test tags declared by the "Tags" suite method.
+//
+//	func (Suite) Tags() map[string][]string {
+//		return map[string][]string{
+//			"TestCheckout": {"integration", "slow"},
+//		}
+//	}
+func suiteTagsOf[Suite any, T fataller](t T) map[string][]string {
+	const name = "Tags"
+
+	suite := reflect.TypeFor[Suite]()
+
+	method, ok := suite.MethodByName(name)
+	if !ok {
+		return nil
+	}
+
+	f, ok := method.Func.Interface().(func(Suite) map[string][]string)
+	if !ok {
+		t.Fatalf(
+			"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s() map[string][]string",
+			suite, name,
+		)
+
+		return nil
+	}
+
+	tags := f(reflectutil.Make[Suite]())
+
+	var unknown []string
+
+	for test := range tags {
+		m, ok := suite.MethodByName(test)
+
+		isKnown := ok && (isTestMethod(m.Name) ||
+			isTest(m.Name, "Fuzz") ||
+			isTest(m.Name, "Benchmark"))
+
+		if !isKnown {
+			unknown = append(unknown, test)
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+
+		t.Fatalf(
+			"wrong tags for %[1]s: tests %[2]s not found",
+			suite, strings.Join(unknown, ", "),
+		)
+
+		return nil
+	}
+
+	return tags
+}
+
+// withTags returns the test with the given tags added to its info.
+func withTags[Suite any, T any](test suiteTest[Suite, T], tags []string) suiteTest[Suite, T] {
+	if len(tags) == 0 {
+		return test
+	}
+
+	merge := func(own []string) []string {
+		merged := slices.Clone(tags)
+
+		for _, tag := range own {
+			if !slices.Contains(merged, tag) {
+				merged = append(merged, tag)
+			}
+		}
+
+		return merged
+	}
+
+	switch info := test.Info.(type) {
+	case plugin.RegularTestInfo:
+		info.Tags = merge(info.Tags)
+		test.Info = info
+
+	case plugin.ParametrizedTestInfo:
+		info.Tags = merge(info.Tags)
+		test.Info = info
+	}
+
+	return test
+}
+
+// tagsOf returns tags of the test with the given info.
+func tagsOf(info plugin.TestInfo) []string {
+	switch info := info.(type) {
+	case plugin.RegularTestInfo:
+		return info.Tags
+
+	case plugin.ParametrizedTestInfo:
+		return info.Tags
+
+	default:
+		return nil
+	}
+}
+
+// tagsPlanOf returns the plan which keeps only the tests
+// matching the tags expression given by the -testo.tags flag.
+//
+// It is applied before the plans of plugins.
+func tagsPlanOf(t fataller) plugin.Plan {
+	if *tagsFlag == "" {
+		return plugin.Plan{}
+	}
+
+	expr, err := tagexpr.Parse(*tagsFlag)
+	if err != nil {
+		t.Fatalf("invalid -testo.tags flag: %v", err)
+
+		return plugin.Plan{}
+	}
+
+	return plugin.Plan{
+		Modify: func(tests *[]plugin.PlannedTest) {
+			*tests = slices.DeleteFunc(*tests, func(test plugin.PlannedTest) bool {
+				return !expr.Match(tagsOf(test.Info()))
+			})
+		},
+	}
+}
@@ -2,10 +2,14 @@
 package testo
 
 import (
+	"cmp"
 	"fmt"
 	"reflect"
 	"runtime/debug"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/metafates/testo/internal/reflectutil"
 	"github.com/metafates/testo/internal/stack"
@@ -29,13 +33,7 @@ const parallelWrapperTest = "testo!"
 func RunSuite[Suite any, T CommonT](t *testing.T, options ...plugin.Option) {
 	t.Helper()
 
-	if !reflectutil.IsSinglePointer(reflect.TypeFor[Suite]()) {
-		panic(fmt.Sprintf(
-			"invalid suite type specified '%s', did you mean '*%[2]s'?",
-			reflect.TypeFor[Suite](),
-			reflectutil.Elem(reflect.TypeFor[Suite]()),
-		))
-	}
+	checkSuiteType[Suite]()
 
 	suiteName := reflectutil.NameOf[Suite]()
 
@@ -55,15 +53,56 @@ func RunSuite[Suite any, T CommonT](t *testing.T, options ...plugin.Option) {
 	})
 }
 
+// checkSuiteType panics if the suite type is not a single pointer.
+func checkSuiteType[Suite any]() {
+	if !reflectutil.IsSinglePointer(reflect.TypeFor[Suite]()) {
+		panic(fmt.Sprintf(
+			"invalid suite type specified '%s', did you mean '*%[2]s'?",
+			reflect.TypeFor[Suite](),
+			reflectutil.Elem(reflect.TypeFor[Suite]()),
+		))
+	}
+}
+
 func runSuite[Suite any, T CommonT](t T) {
 	t.Helper()
 
+	cases := suiteCasesOf[Suite](t)
+	gens := suiteGensOf[Suite](t)
+	fixtures := suiteFixturesOf[Suite, T](t)
+	tests := testsFor(t, cases, gens, fixtures)
+
+	tests.Plans = []plugin.Plan{
+		tagsPlanOf(t),
+		shardPlanOf(t, t.unwrap().SuiteName(), tests.Dependencies),
+	}
+
+	runSuiteTests(t, fixtures, tests)
+}
+
+// runSuiteTests runs the given tests of the suite along with suite and plugin hooks.
+func runSuiteTests[Suite any, T CommonT](
+	t T,
+	fixtures suiteFixtures[Suite, T],
+	tests suiteTests[Suite, T],
+) {
+	t.Helper()
+
 	suiteHooks := suiteHooksOf[Suite](t)
 
 	suite := reflectutil.Make[Suite]()
 
-	cases := suiteCasesOf[Suite](t)
-	tests := testsFor(t, cases)
+	outcomes := newTestOutcomes()
+
+	defer t.unwrap().finishSuite(outcomes)
+
+	t.unwrap().emit(func(meta plugin.EventMeta) plugin.Event {
+		return plugin.SuiteStarted{
+			EventMeta: meta,
+			Suite:     t.unwrap().SuiteName(),
+			SuiteType: reflect.TypeFor[Suite](),
+		}
+	})
 
 	t.unwrap().plugin.Hooks.BeforeAll.Run()
 	suiteHooks.BeforeAll(suite, t)
@@ -73,42 +112,172 @@ func runSuite[Suite any, T CommonT](t T) {
 		t.unwrap().plugin.Hooks.AfterAll.Run()
 	}()
 
+	fixtures.Setup(t, suite, tests.Fixtures)
+
+	retry := retryPolicyOf(t.unwrap().options())
+	timeout := timeoutOf(t.unwrap().options())
+
+	planned := applyPlan(
+		tests.Get(cloneSuite(suite)),
+		append(slices.Clone(tests.Plans), t.unwrap().plugin.Plan)...,
+	)
+
+	for _, test := range planned {
+		outcomes.Plan(rawBaseNameOf(test.Info))
+
+		t.unwrap().emit(func(meta plugin.EventMeta) plugin.Event {
+			return plugin.TestPlanned{EventMeta: meta, Name: test.Name, Info: test.Info, Skip: test.Skip}
+		})
+	}
+
 	t.unwrap().T.Run(parallelWrapperTest, func(rawT *testing.T) {
-		tests := tests.Get(cloneSuite(suite))
-		tests = applyPlan(t.unwrap().plugin.Plan, tests)
+		for _, test := range dependencyOrder(planned, tests.Dependencies) {
+			name := rawBaseNameOf(test.Info)
+
+			if reason := outcomes.Blocked(tests.Dependencies[name]); reason != "" {
+				test.Skip = reason
+			}
 
-		for _, test := range tests {
 			rawT.Run(test.Name, func(rawT *testing.T) {
-				innerT := construct(
-					rawT,
-					&t,
-					func(t *actualT) {
-						t.info.Test = test.Info
-					},
-				)
+				defer outcomes.Record(name, rawT)
 
-				runSuiteTest(
-					innerT,
-					cloneSuite(suite),
+				runSuiteTestAttempts(
+					rawT,
+					t,
+					suite,
 					suiteHooks,
 					test,
+					retry,
+					cmp.Or(test.Timeout, timeout),
 				)
 			})
 		}
 	})
 }
 
+// runSuiteTestAttempts runs the suite test until it passes
+// or retry policy is exhausted.
+func runSuiteTestAttempts[Suite any, T CommonT](
+	rawT *testing.T,
+	parent T,
+	suite Suite,
+	hooks suiteHooks[Suite, T],
+	test suiteTest[Suite, T],
+	retry RetryPolicy,
+	timeout time.Duration,
+) {
+	var (
+		parallel bool
+		names    = newSubtestNames()
+	)
+
+	for attempt := 1; ; attempt++ {
+		isLast := attempt > retry.Retries
+
+		names.nextAttempt()
+
+		// Attempts which could be retried must be torn down before the next one,
+		// so they do not use cleanups of the shared rawT.
+		var lifetime *lifetime
+
+		if !isLast {
+			lifetime = newLifetime(parent.unwrap().Context())
+		}
+
+		t := construct(
+			rawT,
+			&parent,
+			func(t *actualT) {
+				t.info.Test = test.Info
+				t.info.Attempt = attempt
+				t.captureFailures = !isLast
+				t.parallel = parallel
+				t.sequential = test.Sequential
+				t.lifetime = lifetime
+				t.subtestNames = names
+			},
+		)
+
+		run := func() {
+			t.unwrap().start()
+
+			// timed out tests may never return, so they are finished here
+			defer t.unwrap().finish()
+
+			if timeout > 0 {
+				runWithTimeout(t.unwrap(), timeout, func() {
+					runSuiteTest(t, cloneSuite(suite), hooks, test)
+				})
+
+				return
+			}
+
+			runSuiteTest(t, cloneSuite(suite), hooks, test)
+		}
+
+		if isLast {
+			run()
+
+			return
+		}
+
+		probe(run)
+
+		lifetime.End()
+
+		parallel = t.unwrap().parallel
+
+		if !t.unwrap().failed() || rawT.Skipped() {
+			return
+		}
+
+		rawT.Logf("attempt %d of %d failed, retrying", attempt, retry.Retries+1)
+
+		time.Sleep(retry.delay(attempt + 1))
+	}
+}
+
 func runSuiteTest[Suite any, T CommonT](
 	t T,
 	s Suite,
 	hooks suiteHooks[Suite, T],
 	test suiteTest[Suite, T],
 ) {
+	run := test.Run
+
+	// Params of property tests are known only after their inputs are checked,
+	// so it is done before the hooks, see [suiteProperty.Probe].
+	if test.Probe != nil && test.Skip == "" {
+		t.unwrap().setStage("property inputs")
+
+		run = test.Probe(s, t, hooks)
+	}
+
+	t.unwrap().setStage("plugin BeforeEach hooks")
 	t.unwrap().plugin.Hooks.BeforeEach.Run()
+
+	// Skipped tests are still visible to plugins,
+	// but suite hooks are not run for them.
+	if test.Skip != "" {
+		defer t.unwrap().plugin.Hooks.AfterEach.Run()
+
+		t.Skip(test.Skip)
+	}
+
+	// BeforeEach hook may start subtests and fail
+	defer t.unwrap().closeSubtests()
+
+	t.unwrap().setStage("BeforeEach hook")
 	hooks.BeforeEach(s, t)
 
 	defer func() {
+		t.unwrap().setStage("subtests")
+		t.unwrap().closeSubtests()
+
+		t.unwrap().setStage("AfterEach hook")
 		hooks.AfterEach(s, t)
+
+		t.unwrap().setStage("plugin AfterEach hooks")
 		t.unwrap().plugin.Hooks.AfterEach.Run()
 	}()
 
@@ -119,11 +288,12 @@ func runSuiteTest[Suite any, T CommonT](
 				Trace: string(debug.Stack()),
 			}
 
-			t.Fatalf("Test %q panicked: %r", t.Name(), r)
+			t.Fatalf("test %q panicked: %v", t.Name(), r)
 		}
 	}()
 
-	test.Run(s, t)
+	t.unwrap().setStage("test")
+	run(s, t)
 }
 
 // Run a subtest.
@@ -137,9 +307,35 @@ func Run[T CommonT](
 ) bool {
 	t.Helper()
 
+	if t.unwrap().isAbandoned() {
+		return false
+	}
+
+	if t.unwrap().probing {
+		return runProbing(t, name, f, options...)
+	}
+
 	parentT := t
 
-	return parentT.unwrap().T.Run(name, func(tt *testing.T) {
+	var (
+		child *actualT
+		done  = make(chan struct{})
+	)
+
+	var (
+		group  = parentT.unwrap().subtestGroup()
+		paused = make(chan struct{})
+	)
+
+	var actualSuffix, expectedSuffix string
+
+	if names := parentT.unwrap().subtestNames; names != nil {
+		actualSuffix, expectedSuffix = names.add(name)
+	}
+
+	run := func(tt *testing.T) {
+		defer close(done)
+
 		t := construct(
 			tt,
 			&parentT,
@@ -148,26 +344,144 @@ func Run[T CommonT](
 					RawBaseName: name,
 					Level:       t.level(),
 				}
+				t.info.Attempt = t.parent.info.Attempt
+				t.captureFailures = t.parent.captureFailures
+				t.probing = t.parent.probing
+				t.isGrouped = group != nil
+				t.paused = paused
+
+				// Subtest is named after its parent if it was renamed.
+				if t.parent.testName != "" || actualSuffix != expectedSuffix {
+					local := strings.TrimPrefix(tt.Name(), t.parent.T.Name()+"/")
+					local = strings.TrimSuffix(local, actualSuffix) + expectedSuffix
+
+					t.testName = t.parent.name() + "/" + local
+				}
 			},
 			options...,
 		)
 
+		child = t.unwrap()
+
+		parentT.unwrap().addSubtest(child)
+
+		// Captured failures are not seen by [testing.T],
+		// so we have to propagate them manually.
+		// Cleanup runs after all nested subtests, including parallel ones, have finished.
+		// Subtests run in the group are propagated by [T.closeSubtests].
+		if child.captureFailures && !child.probing {
+			// Registered first to run last, after failures of nested subtests are propagated.
+			tt.Cleanup(child.markCapturedFailure)
+		}
+
+		if child.captureFailures && !child.isGrouped {
+			tt.Cleanup(child.propagateFailure)
+		}
+
+		child.setStage(fmt.Sprintf("subtest %q", name))
+
+		parentT.unwrap().active.Store(child)
+		defer parentT.unwrap().active.CompareAndSwap(child, nil)
+
+		t.unwrap().start()
+		defer t.unwrap().finish()
+
 		t.unwrap().plugin.Hooks.BeforeEachSub.Run()
 		defer t.unwrap().plugin.Hooks.AfterEachSub.Run()
 
-		defer func() {
-			if r := recover(); r != nil {
-				t.unwrap().info.Panic = &plugin.PanicInfo{
-					Value: r,
-					Trace: string(debug.Stack()),
+		run := func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.unwrap().info.Panic = &plugin.PanicInfo{
+						Value: r,
+						Trace: string(debug.Stack()),
+					}
+
+					t.Errorf("test %q panicked: %v", t.Name(), r)
 				}
+			}()
+
+			f(t)
+		}
+
+		switch timeout := timeoutOf(options); {
+		case timeout > 0:
+			runWithTimeout(t.unwrap(), timeout, run)
+
+		case t.unwrap().captureFailures:
+			// Captured fatal failures exit the goroutine,
+			// which must not be the one of [testing.T].
+			probe(run)
+
+		default:
+			run()
+		}
+	}
 
-				t.Errorf("test %q panicked: %v", t.Name(), r)
+	var ok bool
+
+	if group != nil {
+		ok = parentT.unwrap().runGrouped(group, name, paused, run)
+	} else {
+		ok = parentT.unwrap().T.Run(name, run)
+	}
+
+	// Subtest may still be running if it is parallel,
+	// its failures are propagated once it finishes.
+	if parentT.unwrap().captureFailures {
+		select {
+		case <-done:
+			if child.info.FailureKind != plugin.TestFailureKindNone {
+				ok = false
+			}
+
+		default:
+		}
+	}
+
+	return ok
+}
+
+// runProbing runs the subtest of the property test input being checked.
+// Such subtests are not reported, so they are run in place of the parent [testing.T].
+func runProbing[T CommonT](
+	parent T,
+	name string,
+	f func(t T),
+	options ...plugin.Option,
+) bool {
+	lifetime := newLifetime(parent.unwrap().Context())
+	defer lifetime.End()
+
+	t := construct(
+		parent.unwrap().T,
+		&parent,
+		func(t *actualT) {
+			t.info.Test = plugin.RegularTestInfo{
+				RawBaseName: name,
+				Level:       t.level(),
+			}
+			t.info.Attempt = t.parent.info.Attempt
+			t.captureFailures = true
+			t.probing = true
+			t.lifetime = lifetime
+		},
+		options...,
+	)
+
+	probe(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.unwrap().info.FailureKind = plugin.TestFailureKindFatal
 			}
 		}()
 
 		f(t)
 	})
+
+	t.unwrap().propagateFailure()
+
+	return t.unwrap().info.FailureKind == plugin.TestFailureKindNone
 }
 
 // construct will construct a new user T (inherits actual T)
@@ -184,10 +498,13 @@ func construct[T CommonT](
 		T:            t,
 		levelOptions: options,
 		plugin:       plugin.MergeSpecs(),
+		started:      time.Now(),
 	}
 
 	if parent != nil {
 		seedT.parent = (*parent).unwrap()
+		seedT.events = seedT.parent.events
+		seedT.id = seedT.events.nextID()
 	}
 
 	if fill != nil {
@@ -200,12 +517,37 @@ func construct[T CommonT](
 		return any(&seedT).(T)
 	}
 
-	value := reflectutil.Filled[T]()
+	value, plugins := newValue(&seedT, parent)
+
+	seedT.info.Plugins = plugins
+	seedT.plugin = mergePlugins(plugins...)
+
+	if parent == nil {
+		seedT.events = newEventStream(seedT.plugin.Observer)
+	}
+
+	return value
+}
+
+// core is implemented by [T] and [B].
+// It is shared by all the plugins of the user type.
+type core interface {
+	Helper()
+	Fatalf(format string, args ...any)
+	options() []plugin.Option
+}
+
+// newValue returns a new value of the user type V sharing the given core
+// and plugins collected from it.
+func newValue[V any](c core, parent *V) (V, []plugin.Plugin) {
+	c.Helper()
+
+	value := reflectutil.Filled[V]()
 
 	inits := stack.New[func()]()
 
 	initValue(
-		&seedT,
+		c,
 		reflect.ValueOf(&value),
 		reflect.ValueOf(parent),
 		&inits,
@@ -222,12 +564,7 @@ func construct[T CommonT](
 		init()
 	}
 
-	plugins := plugin.Collect(&value)
-
-	seedT.info.Plugins = plugins
-	seedT.plugin = mergePlugins(plugins...)
-
-	return value
+	return value, plugin.Collect(&value)
 }
 
 func mergePlugins(plugins ...plugin.Plugin) plugin.Spec {
@@ -242,7 +579,7 @@ func mergePlugins(plugins ...plugin.Plugin) plugin.Spec {
 
 //nolint:cyclop,funlen // splitting it would make it even more complex
 func initValue(
-	t *T,
+	t core,
 	value, parent reflect.Value,
 	inits *stack.Stack[func()],
 ) {
@@ -259,7 +596,7 @@ func initValue(
 	if value.Type() == reflect.TypeOf(t) {
 		if !value.CanAddr() {
 			// TODO: add path to the field so that it is clear where error happens
-			panic(fmt.Sprintf("using non-pointer value of %s", reflect.TypeFor[T]()))
+			panic(fmt.Sprintf("using non-pointer value of %s", reflect.TypeOf(t).Elem()))
 		}
 
 		value.Set(reflect.ValueOf(t))
@@ -327,9 +664,9 @@ func initValue(
 	}
 }
 
-func applyPlan[Suite any, T CommonT](
-	plan plugin.Plan,
+func applyPlan[Suite any, T any](
 	tests []suiteTest[Suite, T],
+	plans ...plugin.Plan,
 ) []suiteTest[Suite, T] {
 	plannedTests := make([]plugin.PlannedTest, 0, len(tests))
 
@@ -337,7 +674,11 @@ func applyPlan[Suite any, T CommonT](
 		plannedTests = append(plannedTests, plannedSuiteTest[Suite, T]{t})
 	}
 
-	plan.Modify(&plannedTests)
+	for _, plan := range plans {
+		if plan.Modify != nil {
+			plan.Modify(&plannedTests)
+		}
+	}
 
 	testsToReturn := make([]suiteTest[Suite, T], 0, len(plannedTests))
 
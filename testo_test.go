@@ -1,8 +1,21 @@
 package testo
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/metafates/testo/internal/maputil"
 	"github.com/metafates/testo/plugin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -233,3 +246,1255 @@ func (s TestSuite) TestFoo(t *TestT) {
 }
 
 func (s *TestSuite) TestBar(t *TestT) {}
+
+type (
+	FixtureSuite struct{}
+
+	FixtureConfig struct{ Name string }
+	FixtureDB     struct{ Config *FixtureConfig }
+	FixtureTx     struct{ DB *FixtureDB }
+)
+
+var fixtureEvents []string
+
+func TestRunSuite_Fixtures(t *testing.T) {
+	fixtureEvents = nil
+
+	RunSuite[*FixtureSuite, *TestT](t)
+
+	assert.Equal(t, []string{
+		"config",
+		"db",
+		"tx TestRunSuite_Fixtures/FixtureSuite/TestBar",
+		"test TestRunSuite_Fixtures/FixtureSuite/TestBar",
+		"tx done",
+		"tx TestRunSuite_Fixtures/FixtureSuite/TestFoo_case_1",
+		"test TestRunSuite_Fixtures/FixtureSuite/TestFoo_case_1",
+		"tx done",
+		"db done",
+	}, fixtureEvents)
+}
+
+func (FixtureSuite) Scopes() map[string]Scope {
+	return map[string]Scope{
+		"Config": ScopeSuite,
+		"DB":     ScopeSuite,
+	}
+}
+
+func (FixtureSuite) FixtureConfig(t *TestT) *FixtureConfig {
+	fixtureEvents = append(fixtureEvents, "config")
+
+	return &FixtureConfig{Name: "test"}
+}
+
+func (FixtureSuite) FixtureDB(t *TestT, config *FixtureConfig) *FixtureDB {
+	fixtureEvents = append(fixtureEvents, "db")
+
+	t.Cleanup(func() { fixtureEvents = append(fixtureEvents, "db done") })
+
+	return &FixtureDB{Config: config}
+}
+
+func (FixtureSuite) FixtureTx(t *TestT, db *FixtureDB) *FixtureTx {
+	fixtureEvents = append(fixtureEvents, "tx "+t.Name())
+
+	t.Cleanup(func() { fixtureEvents = append(fixtureEvents, "tx done") })
+
+	return &FixtureTx{DB: db}
+}
+
+func (FixtureSuite) CasesX() []int { return []int{1} }
+
+func (FixtureSuite) TestFoo(t *TestT, params struct{ X int }, tx *FixtureTx) {
+	assert.Equal(t, 1, params.X)
+	assert.Equal(t, "test", tx.DB.Config.Name)
+
+	fixtureEvents = append(fixtureEvents, "test "+t.Name())
+}
+
+func (FixtureSuite) TestBar(t *TestT, tx *FixtureTx, db *FixtureDB) {
+	assert.Same(t, db, tx.DB)
+
+	fixtureEvents = append(fixtureEvents, "test "+t.Name())
+}
+
+type (
+	RunFixtureSuite      struct{}
+	OtherRunFixtureSuite struct{}
+
+	RunFixtureDir struct {
+		T    *TestT
+		Path string
+	}
+)
+
+var runFixtureDirs []*RunFixtureDir
+
+func TestMain(m *testing.M) {
+	Main(m)
+}
+
+func TestRunSuite_RunFixtures(t *testing.T) {
+	runFixtureDirs = nil
+
+	RunSuite[*RunFixtureSuite, *TestT](t)
+	RunSuite[*OtherRunFixtureSuite, *TestT](t)
+
+	require.Len(t, runFixtureDirs, 2)
+	assert.Same(t, runFixtureDirs[0], runFixtureDirs[1])
+}
+
+func (RunFixtureSuite) Scopes() map[string]Scope {
+	return map[string]Scope{"Dir": ScopeRun}
+}
+
+func (RunFixtureSuite) FixtureDir(t *TestT) *RunFixtureDir {
+	return &RunFixtureDir{T: t, Path: t.TempDir()}
+}
+
+func (RunFixtureSuite) TestFoo(t *TestT, dir *RunFixtureDir) {
+	runFixtureDirs = append(runFixtureDirs, dir)
+}
+
+func (OtherRunFixtureSuite) Scopes() map[string]Scope {
+	return map[string]Scope{"Dir": ScopeRun}
+}
+
+func (OtherRunFixtureSuite) FixtureDir(t *TestT) *RunFixtureDir {
+	panic("must be created by RunFixtureSuite")
+}
+
+func (OtherRunFixtureSuite) TestFoo(t *TestT, dir *RunFixtureDir) {
+	runFixtureDirs = append(runFixtureDirs, dir)
+
+	// the suite which created the fixture has finished
+	assert.DirExists(t, dir.Path)
+	assert.NoError(t, dir.T.Context().Err())
+
+	dir.T.Log("still usable")
+}
+
+type RetrySuite struct{}
+
+var retryEvents []string
+
+func TestRunSuite_Retry(t *testing.T) {
+	retryEvents = nil
+
+	RunSuite[*RetrySuite, *TestT](t, WithRetry(RetryPolicy{Retries: 2}))
+
+	assert.Equal(t, []string{
+		"before 1", "fatal", "after 1", "cleanup 1",
+		"before 2", "subtest", "after 2", "cleanup 2",
+		"before 3", "pass", "after 3", "cleanup 3",
+	}, retryEvents)
+}
+
+func (RetrySuite) BeforeEach(t *TestT) {
+	retryEvents = append(retryEvents, fmt.Sprint("before ", Inspect(t).Attempt))
+}
+
+func (RetrySuite) AfterEach(t *TestT) {
+	retryEvents = append(retryEvents, fmt.Sprint("after ", Inspect(t).Attempt))
+}
+
+type RetryResource struct{}
+
+func (RetrySuite) FixtureResource(t *TestT) *RetryResource {
+	attempt := Inspect(t).Attempt
+
+	t.Cleanup(func() { retryEvents = append(retryEvents, fmt.Sprint("cleanup ", attempt)) })
+
+	return &RetryResource{}
+}
+
+func (RetrySuite) TestFlaky(t *TestT, _ *RetryResource) {
+	t.Parallel()
+
+	switch Inspect(t).Attempt {
+	case 1:
+		retryEvents = append(retryEvents, "fatal")
+
+		t.Fatal("first attempt fails")
+
+	case 2:
+		retryEvents = append(retryEvents, "subtest")
+
+		Run(t, "subtest", func(t *TestT) { t.Error("second attempt fails") })
+
+	default:
+		retryEvents = append(retryEvents, "pass")
+	}
+}
+
+type NestedRetrySuite struct{}
+
+var nestedRetryAttempts []int
+
+func TestRunSuite_RetryNestedParallel(t *testing.T) {
+	nestedRetryAttempts = nil
+
+	RunSuite[*NestedRetrySuite, *TestT](t, WithRetry(RetryPolicy{Retries: 1}))
+
+	// failure of the first attempt must not be lost
+	assert.Equal(t, []int{1, 2}, nestedRetryAttempts)
+}
+
+func (NestedRetrySuite) TestFoo(t *TestT) {
+	attempt := Inspect(t).Attempt
+
+	nestedRetryAttempts = append(nestedRetryAttempts, attempt)
+
+	Run(t, "outer", func(t *TestT) {
+		Run(t, "inner", func(t *TestT) {
+			t.Parallel()
+
+			if attempt == 1 {
+				t.Error("first attempt fails")
+			}
+		})
+	})
+}
+
+type TimeoutSuite struct{}
+
+var timeoutEvents []string
+
+func TestRunSuite_Timeout(t *testing.T) {
+	timeoutEvents = nil
+
+	RunSuite[*TimeoutSuite, *TestT](t, WithRetry(RetryPolicy{Retries: 1}))
+
+	assert.Equal(t, []string{
+		"attempt 1: test timed out after 10ms",
+		"attempt 2: <nil>",
+	}, timeoutEvents)
+}
+
+func (TimeoutSuite) Timeouts() map[string]time.Duration {
+	return map[string]time.Duration{"TestSlow": 10 * time.Millisecond}
+}
+
+func (TimeoutSuite) AfterEach(t *TestT) {
+	timeoutEvents = append(timeoutEvents, fmt.Sprintf(
+		"attempt %d: %v",
+		Inspect(t).Attempt,
+		context.Cause(t.Context()),
+	))
+}
+
+func (TimeoutSuite) TestSlow(t *TestT) {
+	if Inspect(t).Attempt > 1 {
+		return
+	}
+
+	<-t.Context().Done()
+}
+
+func TestT_describeStage(t *testing.T) {
+	var parent, child T
+
+	parent.setStage("test")
+	child.setStage(`subtest "foo"`)
+	parent.active.Store(&child)
+
+	assert.Equal(t, `test > subtest "foo"`, parent.describeStage())
+}
+
+type PropertySuite struct {
+	prepared int
+}
+
+type PropertyResource struct {
+	closed bool
+}
+
+var propertyParams []map[string]any
+
+func TestRunSuite_Property(t *testing.T) {
+	propertyParams = nil
+
+	RunSuite[*PropertySuite, *TestT](t, WithRetry(RetryPolicy{Retries: 1}))
+
+	require.Len(t, propertyParams, 2)
+
+	falsified := propertyParams[0]
+
+	assert.Contains(t, falsified, "seed")
+	assert.Equal(t, 10, falsified["X"])
+	assert.Equal(t, "", falsified["Y"])
+
+	assert.Equal(t, []string{"seed"}, maputil.Keys(propertyParams[1]))
+}
+
+func (PropertySuite) GenX() Gen[int] { return GenInt(0, 1000) }
+
+func (PropertySuite) GenY() Gen[string] { return GenString(10) }
+
+func (s *PropertySuite) BeforeEach(t *TestT) {
+	s.prepared++
+
+	// params are published before the hooks, inputs being checked have none
+	if p, ok := Inspect(t).Test.(plugin.ParametrizedTestInfo); ok && len(p.Params) > 0 {
+		propertyParams = append(propertyParams, p.Params)
+	}
+}
+
+func (PropertySuite) FixtureResource(t *TestT) *PropertyResource {
+	r := &PropertyResource{}
+
+	t.Cleanup(func() { r.closed = true })
+
+	return r
+}
+
+func (s *PropertySuite) PropertyLess(t *TestT, params struct {
+	X int
+	Y string
+}, r *PropertyResource,
+) {
+	// each input is checked with its own suite clone and fixtures
+	require.Equal(t, 1, s.prepared)
+	require.False(t, r.closed)
+
+	r.closed = true
+
+	if Inspect(t).Attempt > 1 {
+		return
+	}
+
+	if params.X%7 == 0 {
+		t.Skip("discarded")
+	}
+
+	Run(t, "sub", func(t *TestT) {
+		if params.X >= 10 {
+			t.Errorf("%d is too big", params.X)
+		}
+	})
+}
+
+type FuzzTargetSuite struct {
+	prepared bool
+}
+
+var fuzzSetUps, fuzzTearDowns atomic.Int32
+
+func FuzzFuzzSuite(f *testing.F) {
+	fuzzSetUps.Store(0)
+	fuzzTearDowns.Store(0)
+
+	// registered first, so it is called after the suite is torn down
+	f.Cleanup(func() {
+		setUps, tearDowns := fuzzSetUps.Load(), fuzzTearDowns.Load()
+
+		if setUps > 1 || setUps != tearDowns {
+			f.Errorf("suite is set up %d times and torn down %d times, want once", setUps, tearDowns)
+		}
+	})
+
+	FuzzSuite[*FuzzTargetSuite, *TestT](f)
+}
+
+func (FuzzTargetSuite) BeforeAll(t *TestT) {
+	fuzzSetUps.Add(1)
+}
+
+func (FuzzTargetSuite) AfterAll(t *TestT) {
+	fuzzTearDowns.Add(1)
+}
+
+func (FuzzTargetSuite) CasesS() []string { return []string{"foo", "héllo"} }
+
+func (FuzzTargetSuite) FixtureConfig(t *TestT) *FixtureConfig {
+	return &FixtureConfig{Name: "fuzz"}
+}
+
+func (FuzzTargetSuite) Scopes() map[string]Scope {
+	return map[string]Scope{"Logger": ScopeSuite}
+}
+
+// FuzzLogger logs with the suite T while fuzzing, which must not use [testing.F].
+type FuzzLogger struct{ T *TestT }
+
+func (FuzzTargetSuite) FixtureLogger(t *TestT) *FuzzLogger {
+	return &FuzzLogger{T: t}
+}
+
+func (s *FuzzTargetSuite) BeforeEach(t *TestT) {
+	s.prepared = true
+}
+
+func (s *FuzzTargetSuite) FuzzReverse(t *TestT, params struct {
+	S string
+	N int
+}, config *FixtureConfig, logger *FuzzLogger,
+) {
+	logger.T.Log("fuzzing", params.S)
+
+	require.True(t, s.prepared, "BeforeEach must be run")
+	require.Equal(t, "fuzz", config.Name)
+
+	p, ok := Inspect(t).Test.(plugin.ParametrizedTestInfo)
+	require.True(t, ok)
+	require.Equal(t, params.S, p.Params["S"])
+
+	reversed := []rune(params.S)
+	slices.Reverse(reversed)
+	slices.Reverse(reversed)
+
+	if string(reversed) != params.S && utf8.ValidString(params.S) {
+		t.Errorf("reversing %q twice gives %q", params.S, string(reversed))
+	}
+}
+
+type BenchPlugin struct{ *B }
+
+func (p BenchPlugin) Plugin() plugin.Spec {
+	return plugin.Spec{
+		Hooks: plugin.Hooks{
+			BeforeAll: plugin.Hook{Func: func() {
+				benchEvents = append(benchEvents, "plugin BeforeAll "+p.SuiteName())
+			}},
+		},
+	}
+}
+
+type BenchB struct {
+	*B
+
+	BenchPlugin
+}
+
+type BenchTargetSuite struct{}
+
+var benchEvents []string
+
+func TestBenchSuite(t *testing.T) {
+	benchEvents = nil
+
+	// keep the test fast
+	benchtime := flag.Lookup("test.benchtime").Value.String()
+
+	require.NoError(t, flag.Set("test.benchtime", "10x"))
+
+	t.Cleanup(func() { _ = flag.Set("test.benchtime", benchtime) })
+
+	result := testing.Benchmark(func(b *testing.B) {
+		BenchSuite[*BenchTargetSuite, *BenchB](b)
+	})
+
+	assert.Positive(t, result.N)
+
+	assert.Equal(t, "plugin BeforeAll BenchTargetSuite", benchEvents[0])
+	assert.Equal(t, "BeforeAll", benchEvents[1])
+	assert.Equal(t, "AfterAll", benchEvents[len(benchEvents)-1])
+	assert.Contains(t, benchEvents, "BenchmarkSum 3")
+	assert.Contains(t, benchEvents, "BenchmarkSum 10")
+}
+
+func (BenchTargetSuite) BeforeAll(b *BenchB) {
+	benchEvents = append(benchEvents, "BeforeAll")
+}
+
+func (BenchTargetSuite) AfterAll(b *BenchB) {
+	benchEvents = append(benchEvents, "AfterAll")
+}
+
+func (BenchTargetSuite) CasesSize() []int { return []int{3, 10} }
+
+func (BenchTargetSuite) BenchmarkSum(b *BenchB, params struct{ Size int }) {
+	benchEvents = append(benchEvents, fmt.Sprint("BenchmarkSum ", params.Size))
+
+	for range b.N {
+		var sum int
+
+		for i := range params.Size {
+			sum += i
+		}
+
+		_ = sum
+	}
+}
+
+type PanicBenchSuite struct{}
+
+var (
+	panicBenchAfterEach atomic.Int32
+	panicBenchFailed    atomic.Bool
+)
+
+func TestBenchSuite_Panic(t *testing.T) {
+	panicBenchAfterEach.Store(0)
+	panicBenchFailed.Store(false)
+
+	testing.Benchmark(func(b *testing.B) {
+		BenchSuite[*PanicBenchSuite, *BenchB](b)
+	})
+
+	assert.True(t, panicBenchFailed.Load(), "panicked benchmark must fail")
+	assert.Positive(t, panicBenchAfterEach.Load(), "AfterEach must be run")
+}
+
+func (PanicBenchSuite) AfterEach(b *BenchB) {
+	panicBenchAfterEach.Add(1)
+}
+
+func (PanicBenchSuite) AfterAll(b *BenchB) {
+	panicBenchFailed.Store(b.Failed())
+}
+
+func (PanicBenchSuite) BenchmarkPanic(b *BenchB) {
+	panic("oops")
+}
+
+type DependencySuite struct{}
+
+var (
+	dependencyMu     sync.Mutex
+	dependencyEvents []string
+)
+
+func TestRunSuite_Dependencies(t *testing.T) {
+	dependencyEvents = nil
+
+	RunSuite[*DependencySuite, *TestT](t)
+
+	assert.Equal(t, []string{
+		"TestB start",
+		"TestB end",
+		"TestRunSuite_Dependencies/DependencySuite/testo!/TestA",
+	}, dependencyEvents, "dependents of skipped TestC must be skipped")
+}
+
+func (DependencySuite) Dependencies() map[string][]string {
+	return map[string][]string{
+		"TestA": {"TestB"},
+		"TestD": {"TestC"},
+		"TestE": {"TestD", "TestA"},
+	}
+}
+
+func (DependencySuite) TestA(t *TestT) {
+	// the wrapper name does not depend on the number of dependency levels
+	dependencyEvent(t.T.T.Name())
+}
+
+func (DependencySuite) TestB(t *TestT) {
+	t.Parallel()
+
+	dependencyEvent("TestB start")
+	time.Sleep(10 * time.Millisecond)
+	dependencyEvent("TestB end")
+}
+
+func (DependencySuite) TestC(t *TestT) {
+	t.Skip("not today")
+}
+
+func (DependencySuite) TestD(t *TestT) {
+	dependencyEvent("TestD")
+}
+
+func (DependencySuite) TestE(t *TestT) {
+	dependencyEvent("TestE")
+}
+
+func dependencyEvent(event string) {
+	dependencyMu.Lock()
+	defer dependencyMu.Unlock()
+
+	dependencyEvents = append(dependencyEvents, event)
+}
+
+func TestTestOutcomes_Blocked(t *testing.T) {
+	outcomes := newTestOutcomes()
+
+	passed, failed := &testing.T{}, &testing.T{}
+	failed.Fail()
+
+	outcomes.Record("TestCreate", passed)
+	outcomes.Record("TestUpdate", passed)
+	outcomes.Record("TestUpdate", failed)
+
+	assert.Empty(t, outcomes.Blocked([]string{"TestCreate"}))
+	assert.Equal(t, "prerequisite TestUpdate failed", outcomes.Blocked([]string{"TestCreate", "TestUpdate"}))
+	assert.Equal(t,
+		"prerequisite TestDelete did not run, it is filtered out by the plan, e.g. -testo.tags flag",
+		outcomes.Blocked([]string{"TestDelete"}),
+	)
+
+	// planned test has no outcome if it does not match -run flag
+	outcomes.Plan("TestDelete")
+
+	assert.Equal(t,
+		"prerequisite TestDelete did not run, it is filtered out by -run or -skip flag",
+		outcomes.Blocked([]string{"TestDelete"}),
+	)
+}
+
+type ParallelSubtestsSuite struct{}
+
+var parallelSubtestsEvents []string
+
+func TestRunSuite_ParallelSubtests(t *testing.T) {
+	parallelSubtestsEvents = nil
+
+	RunSuite[*ParallelSubtestsSuite, *TestT](t)
+
+	// parallel subtests may finish in any order
+	slices.Sort(parallelSubtestsEvents[1:3])
+
+	assert.Equal(t, []string{
+		"body end",
+		"TestRunSuite_ParallelSubtests/ParallelSubtestsSuite/TestFoo/a",
+		"TestRunSuite_ParallelSubtests/ParallelSubtestsSuite/TestFoo/b",
+		"AfterEach",
+		"AfterEach subtest",
+	}, parallelSubtestsEvents)
+}
+
+func (ParallelSubtestsSuite) AfterEach(t *TestT) {
+	parallelSubtestsEvents = append(parallelSubtestsEvents, "AfterEach")
+
+	Run(t, "teardown", func(t *TestT) {
+		parallelSubtestsEvents = append(parallelSubtestsEvents, "AfterEach subtest")
+	})
+}
+
+func (ParallelSubtestsSuite) TestFoo(t *TestT) {
+	var mu sync.Mutex
+
+	for _, name := range []string{"a", "b"} {
+		Run(t, name, func(t *TestT) {
+			t.Parallel()
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			parallelSubtestsEvents = append(parallelSubtestsEvents, t.Name())
+		})
+	}
+
+	parallelSubtestsEvents = append(parallelSubtestsEvents, "body end")
+}
+
+type ParallelLimitSuite struct{}
+
+var (
+	parallelRunning, parallelMaxRunning atomic.Int32
+	parallelSetenvPanicked              atomic.Bool
+)
+
+func TestRunSuite_ParallelSubtestsLimit(t *testing.T) {
+	parallelRunning.Store(0)
+	parallelMaxRunning.Store(0)
+	parallelSetenvPanicked.Store(false)
+
+	RunSuite[*ParallelLimitSuite, *TestT](t)
+
+	limit, err := strconv.Atoi(flag.Lookup("test.parallel").Value.String())
+	require.NoError(t, err)
+
+	maxRunning := int(parallelMaxRunning.Load())
+
+	assert.LessOrEqual(t, maxRunning, limit, "-test.parallel limit must hold")
+	assert.GreaterOrEqual(t, maxRunning, min(limit, 2), "parallel subtests must overlap")
+	assert.True(t, parallelSetenvPanicked.Load(), "Setenv must not be allowed in parallel subtests")
+}
+
+func (ParallelLimitSuite) TestFoo(t *TestT) {
+	for i := range 8 {
+		Run(t, strconv.Itoa(i), func(t *TestT) {
+			t.Parallel()
+
+			running := parallelRunning.Add(1)
+			defer parallelRunning.Add(-1)
+
+			for {
+				maxRunning := parallelMaxRunning.Load()
+				if running <= maxRunning || parallelMaxRunning.CompareAndSwap(maxRunning, running) {
+					break
+				}
+			}
+
+			// give other subtests a chance to start
+			time.Sleep(20 * time.Millisecond)
+		})
+	}
+
+	Run(t, "setenv", func(t *TestT) {
+		t.Parallel()
+
+		defer func() {
+			parallelSetenvPanicked.Store(recover() != nil)
+		}()
+
+		t.Setenv("TESTO_PARALLEL", "1")
+	})
+}
+
+type SubtestFilterSuite struct{}
+
+func TestRunSuite_SubtestFilter(t *testing.T) {
+	if os.Getenv("TESTO_SUBTEST_FILTER") != "" {
+		RunSuite[*SubtestFilterSuite, *TestT](t)
+
+		return
+	}
+
+	//nolint:gosec // runs this test binary
+	cmd := exec.Command(
+		os.Args[0],
+		"-test.run", "^TestRunSuite_SubtestFilter$/SubtestFilterSuite/testo!/TestFoo/b$",
+		"-test.v",
+		"-test.timeout", "30s",
+	)
+	cmd.Env = append(os.Environ(), "TESTO_SUBTEST_FILTER=1")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "%s", out)
+
+	assert.Contains(t, string(out), "--- PASS: TestRunSuite_SubtestFilter/SubtestFilterSuite/testo!/TestFoo/b ")
+	assert.NotContains(t, string(out), "TestFoo/a")
+}
+
+func (SubtestFilterSuite) TestFoo(t *TestT) {
+	for _, name := range []string{"a", "b"} {
+		Run(t, name, func(t *TestT) {
+			t.Parallel()
+		})
+	}
+}
+
+type TagsSuite struct{}
+
+var tagsEvents []string
+
+func TestRunSuite_Tags(t *testing.T) {
+	tagsEvents = nil
+
+	require.NoError(t, flag.Set("testo.tags", "integration && !slow"))
+
+	t.Cleanup(func() { _ = flag.Set("testo.tags", "") })
+
+	RunSuite[*TagsSuite, *TestT](t)
+
+	assert.ElementsMatch(t, []string{
+		"TestFast [integration]",
+		"TestParams [integration db:postgres]",
+	}, tagsEvents)
+}
+
+func (TagsSuite) Tags() map[string][]string {
+	return map[string][]string{
+		"TestFast":   {"integration"},
+		"TestSlow":   {"integration", "slow"},
+		"TestParams": {"integration"},
+	}
+}
+
+func (TagsSuite) CasesDB() []Case[string] {
+	return []Case[string]{
+		{Name: "postgres", Value: "postgres", Tags: []string{"db:postgres"}},
+		{Name: "sqlite", Value: "sqlite", Tags: []string{"slow"}},
+	}
+}
+
+func (TagsSuite) TestFast(t *TestT) {
+	tagsEvents = append(tagsEvents, fmt.Sprint("TestFast ", tagsOf(Inspect(t).Test)))
+}
+
+func (TagsSuite) TestSlow(t *TestT) {
+	tagsEvents = append(tagsEvents, fmt.Sprint("TestSlow ", tagsOf(Inspect(t).Test)))
+}
+
+func (TagsSuite) TestUntagged(t *TestT) {
+	tagsEvents = append(tagsEvents, "TestUntagged")
+}
+
+func (TagsSuite) TestParams(t *TestT, params struct{ DB string }) {
+	tagsEvents = append(tagsEvents, fmt.Sprint("TestParams ", tagsOf(Inspect(t).Test)))
+}
+
+type (
+	FocusSuite   struct{}
+	PendingSuite struct{}
+)
+
+var focusEvents []string
+
+func TestRunSuite_Focus(t *testing.T) {
+	focusEvents = nil
+
+	RunSuite[*FocusSuite, *TestT](t)
+
+	assert.Equal(t, []string{"FTestA"}, focusEvents)
+}
+
+func (FocusSuite) FTestA(t *TestT) {
+	focusEvents = append(focusEvents, "FTestA")
+}
+
+func (FocusSuite) TestB(t *TestT) {
+	focusEvents = append(focusEvents, "TestB")
+}
+
+func TestRunSuite_Pending(t *testing.T) {
+	focusEvents = nil
+
+	RunSuite[*PendingSuite, *TestT](t)
+
+	assert.Equal(t, []string{"TestA"}, focusEvents)
+}
+
+func (PendingSuite) TestA(t *TestT) {
+	focusEvents = append(focusEvents, "TestA")
+}
+
+func (PendingSuite) XTestB(t *TestT) {
+	focusEvents = append(focusEvents, "XTestB")
+}
+
+func (PendingSuite) PendingC(t *TestT, params struct{ Missing int }) {
+	focusEvents = append(focusEvents, "PendingC")
+}
+
+type CaseNamesSuite struct{}
+
+var caseNamesEvents []string
+
+func TestRunSuite_CaseNames(t *testing.T) {
+	caseNamesEvents = nil
+
+	RunSuite[*CaseNamesSuite, *TestT](t)
+
+	assert.ElementsMatch(t, []string{"a_b_c", "a_b_b_c", "a_c", "a_b_c_2"}, caseNamesEvents)
+}
+
+func (CaseNamesSuite) CasesX() map[string]int {
+	return map[string]int{"c": 1, "b_c": 2}
+}
+
+func (CaseNamesSuite) CasesY() map[string]int {
+	return map[string]int{"a_b": 1, "a": 2}
+}
+
+// TestFoo names cases by Y, then X.
+func (CaseNamesSuite) TestFoo(t *TestT, _ struct{ Y, X int }) {
+	//nolint:forcetypeassert // test is parametrized
+	info := Inspect(t).Test.(plugin.ParametrizedTestInfo)
+
+	caseNamesEvents = append(caseNamesEvents, info.CaseName)
+}
+
+type CaseFilesSuite struct{}
+
+type caseFileUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+var caseFilesEvents []string
+
+func TestRunSuite_CaseFiles(t *testing.T) {
+	caseFilesEvents = nil
+
+	RunSuite[*CaseFilesSuite, *TestT](t)
+
+	assert.ElementsMatch(t, []string{
+		"users_alice {Alice 30}",
+		"users_bob {Bob 25}",
+		"users_1 {Erin 52}",
+		"admins_1_users_1 {Frank 60} {Erin 52}",
+	}, caseFilesEvents)
+}
+
+func (CaseFilesSuite) CaseFiles() map[string]string {
+	return map[string]string{
+		"JSONUser":  "users.json",
+		"CSVUser":   "users.csv",
+		"AdminUser": "admins.csv",
+	}
+}
+
+// TestTwoFiles combines rows with the same key from two files.
+func (CaseFilesSuite) TestTwoFiles(t *TestT, params struct{ AdminUser, CSVUser caseFileUser }) {
+	caseFilesEvent(t, params.AdminUser, params.CSVUser)
+}
+
+func (CaseFilesSuite) TestJSON(t *TestT, params struct{ JSONUser caseFileUser }) {
+	caseFilesEvent(t, params.JSONUser)
+}
+
+func (CaseFilesSuite) TestCSV(t *TestT, params struct{ CSVUser caseFileUser }) {
+	caseFilesEvent(t, params.CSVUser)
+}
+
+func caseFilesEvent(t *TestT, users ...caseFileUser) {
+	//nolint:forcetypeassert // test is parametrized
+	info := Inspect(t).Test.(plugin.ParametrizedTestInfo)
+
+	event := info.CaseName
+
+	for _, user := range users {
+		event += fmt.Sprint(" ", user)
+	}
+
+	caseFilesEvents = append(caseFilesEvents, event)
+}
+
+type ShardSuite struct{}
+
+var (
+	shardEventsMu sync.Mutex
+	shardEvents   []string
+)
+
+func shardEvent(name string) {
+	shardEventsMu.Lock()
+	defer shardEventsMu.Unlock()
+
+	shardEvents = append(shardEvents, name)
+}
+
+func TestRunSuite_Shard(t *testing.T) {
+	t.Cleanup(func() { _ = flag.Set("testo.shard", "") })
+
+	var all []string
+
+	for _, shard := range []string{"1/3", "2/3", "3/3"} {
+		shardEvents = nil
+
+		require.NoError(t, flag.Set("testo.shard", shard))
+
+		RunSuite[*ShardSuite, *TestT](t)
+
+		// dependent tests are always in the same shard
+		assert.Equal(t,
+			slices.Contains(shardEvents, "TestA"),
+			slices.Contains(shardEvents, "TestB"),
+			"shard %s: %v", shard, shardEvents,
+		)
+
+		all = append(all, shardEvents...)
+	}
+
+	slices.Sort(all)
+
+	assert.Equal(t, []string{
+		"TestA",
+		"TestB",
+		"TestC",
+		"TestD",
+		"TestE",
+		"TestParams 1",
+		"TestParams 2",
+		"TestParams 3",
+		"TestParams 4",
+	}, all)
+}
+
+func TestRunSuite_ShardCount(t *testing.T) {
+	if os.Getenv("TESTO_SHARD_COUNT") != "" {
+		RunSuite[*ShardSuite, *TestT](t)
+
+		return
+	}
+
+	//nolint:gosec // runs this test binary
+	cmd := exec.Command(
+		os.Args[0],
+		"-test.run", "^TestRunSuite_ShardCount$",
+		"-test.timeout", "30s",
+		"-testo.shard", "1/1",
+	)
+	cmd.Env = append(os.Environ(), "TESTO_SHARD_COUNT=1")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "%s", out)
+
+	// printed without -v flag
+	assert.Contains(t, string(out), "testo: ShardSuite: shard 1/1: running 9 of 9 tests\n")
+}
+
+func (ShardSuite) Dependencies() map[string][]string {
+	return map[string][]string{"TestB": {"TestA"}}
+}
+
+func (ShardSuite) CasesN() []int {
+	return []int{1, 2, 3, 4}
+}
+
+func (ShardSuite) TestA(t *TestT) { shardEvent("TestA") }
+func (ShardSuite) TestB(t *TestT) { shardEvent("TestB") }
+func (ShardSuite) TestC(t *TestT) { shardEvent("TestC") }
+func (ShardSuite) TestD(t *TestT) { shardEvent("TestD") }
+func (ShardSuite) TestE(t *TestT) { shardEvent("TestE") }
+
+func (ShardSuite) TestParams(t *TestT, params struct{ N int }) {
+	shardEvent(fmt.Sprint("TestParams ", params.N))
+}
+
+type ResultSuite struct{}
+
+var (
+	resultEvents   []string
+	resultEventsMu sync.Mutex
+)
+
+type ResultPlugin struct{ *T }
+
+func (p ResultPlugin) Plugin() plugin.Spec {
+	hook := func(name string) plugin.ResultHook {
+		return plugin.ResultHook{Func: func(result plugin.Result) {
+			resultEventsMu.Lock()
+			defer resultEventsMu.Unlock()
+
+			if result.Duration <= 0 {
+				p.Errorf("%s: duration must be positive, got %s", name, result.Duration)
+			}
+
+			resultEvents = append(resultEvents, fmt.Sprintf(
+				"%s %s %d: kind %d: %s",
+				name, rawBaseNameOf(Inspect(p).Test), Inspect(p).Attempt, result.FailureKind, result.Message,
+			))
+		}}
+	}
+
+	return plugin.Spec{
+		Hooks: plugin.Hooks{
+			OnFailure:      hook("OnFailure"),
+			OnSkip:         hook("OnSkip"),
+			OnPanic:        hook("OnPanic"),
+			OnSuiteFailure: hook("OnSuiteFailure"),
+		},
+	}
+}
+
+type ResultT struct {
+	*T
+
+	ResultPlugin
+}
+
+func TestRunSuite_ResultHooks(t *testing.T) {
+	resultEvents = nil
+
+	RunSuite[*ResultSuite, *ResultT](t, WithRetry(RetryPolicy{Retries: 1}))
+
+	assert.ElementsMatch(t, []string{
+		"OnFailure TestError 1: kind 1: error 1\nerror 2",
+		"OnFailure TestFatal 1: kind 2: fatal",
+		`OnFailure TestPanic 1: kind 2: test "TestRunSuite_ResultHooks/ResultSuite/TestPanic" panicked: boom`,
+		`OnPanic TestPanic 1: kind 2: test "TestRunSuite_ResultHooks/ResultSuite/TestPanic" panicked: boom`,
+		"OnFailure sub 1: kind 1: sub fails",
+		"OnFailure TestSubtest 1: kind 1: ",
+		"OnSkip TestSkip 1: kind 0: not now",
+	}, resultEvents)
+}
+
+func TestT_finishSuite(t *testing.T) {
+	resultEvents = nil
+
+	suiteT := &T{
+		T:               t,
+		captureFailures: true,
+		started:         time.Now(),
+	}
+
+	suiteT.plugin = plugin.MergeSpecs(ResultPlugin{T: suiteT}.Plugin())
+
+	outcomes := newTestOutcomes()
+	outcomes.outcomes["TestA"] = testOutcome{Runs: 1}
+	outcomes.outcomes["TestB"] = testOutcome{Runs: 1, Failed: 1}
+	outcomes.outcomes["TestC"] = testOutcome{Runs: 2, Failed: 1}
+
+	suiteT.finishSuite(outcomes)
+
+	assert.Empty(t, resultEvents, "passed suite")
+
+	suiteT.info.FailureKind = plugin.TestFailureKindSoft
+
+	suiteT.finishSuite(outcomes)
+
+	suiteT.addFailure("BeforeAll failed")
+
+	suiteT.finishSuite(outcomes)
+
+	assert.Equal(t, []string{
+		"OnSuiteFailure  0: kind 1: failed tests: TestB, TestC",
+		"OnSuiteFailure  0: kind 1: BeforeAll failed",
+	}, resultEvents)
+}
+
+func (ResultSuite) TestPass(*ResultT) {}
+
+func (ResultSuite) TestError(t *ResultT) {
+	if Inspect(t).Attempt == 1 {
+		t.Error("error", 1)
+		t.Errorf("error %d", 2)
+	}
+}
+
+func (ResultSuite) TestFatal(t *ResultT) {
+	if Inspect(t).Attempt == 1 {
+		t.Fatal("fatal")
+	}
+}
+
+func (ResultSuite) TestPanic(t *ResultT) {
+	if Inspect(t).Attempt == 1 {
+		panic("boom")
+	}
+}
+
+func (ResultSuite) TestSubtest(t *ResultT) {
+	if Inspect(t).Attempt == 1 {
+		Run(t, "sub", func(t *ResultT) { t.Error("sub fails") })
+	}
+}
+
+func (ResultSuite) TestSkip(t *ResultT) {
+	t.Skip("not now")
+}
+
+type EventsSuite struct{}
+
+type EventsPlugin struct {
+	*T
+
+	observed *[]plugin.Event
+}
+
+func (p *EventsPlugin) Init(parent *EventsPlugin, _ ...plugin.Option) {
+	if parent == nil || parent.observed == nil {
+		eventsOfSuite = nil
+		p.observed = &eventsOfSuite
+
+		return
+	}
+
+	p.observed = parent.observed
+}
+
+func (p *EventsPlugin) Plugin() plugin.Spec {
+	return plugin.Spec{
+		Observer: func(event plugin.Event) {
+			*p.observed = append(*p.observed, event)
+
+			if _, ok := event.(plugin.SuiteStarted); ok {
+				// events reported by the observer must not deadlock
+				p.Log("observed")
+			}
+		},
+		Overrides: plugin.Overrides{
+			// logs are reported even if the original Log is not called
+			Log: func(plugin.FuncLog) plugin.FuncLog {
+				return func(...any) {}
+			},
+		},
+	}
+}
+
+type EventsT struct {
+	*T
+
+	EventsPlugin
+}
+
+var eventsOfSuite []plugin.Event
+
+func TestRunSuite_Events(t *testing.T) {
+	RunSuite[*EventsSuite, *EventsT](t, WithRetry(RetryPolicy{Retries: 1}))
+
+	prefix := t.Name() + "/EventsSuite"
+
+	var (
+		got  []string
+		last time.Time
+	)
+
+	started := map[int]bool{0: true}
+
+	for _, event := range eventsOfSuite {
+		meta := event.Meta()
+
+		assert.False(t, meta.Time.Before(last), "events must be ordered")
+		assert.True(t, started[meta.Test.ParentID], "parent must be started")
+
+		last = meta.Time
+
+		name := strings.TrimPrefix(meta.Test.Name, prefix)
+
+		switch e := event.(type) {
+		case plugin.SuiteStarted:
+			got = append(got, "suite started "+e.Suite)
+
+		case plugin.TestPlanned:
+			got = append(got, "planned "+e.Name+" "+e.Skip)
+
+		case plugin.TestStarted:
+			started[meta.Test.ID] = true
+
+			got = append(got, fmt.Sprintf("started %s %d", name, meta.Test.Attempt))
+
+		case plugin.Log:
+			got = append(got, fmt.Sprintf("log %s: %s", name, e.Message))
+
+		case plugin.Failure:
+			got = append(got, fmt.Sprintf("failure %s %d: %s", name, e.Kind, e.Message))
+
+		case plugin.Skip:
+			got = append(got, fmt.Sprintf("skip %s: %s", name, e.Message))
+
+		case plugin.Cleanup:
+			got = append(got, "cleanup "+name)
+
+		case plugin.TestFinished:
+			got = append(got, fmt.Sprintf("finished %s %d %t", name, e.Result.FailureKind, e.Skipped))
+
+		case plugin.SuiteFinished:
+			got = append(got, fmt.Sprintf("suite finished %d", e.Result.FailureKind))
+		}
+	}
+
+	assert.Equal(t, []string{
+		"suite started EventsSuite",
+		"log : observed",
+		"planned TestA ",
+		"planned TestB ",
+		"planned TestC ",
+		"started /TestA 1",
+		"log /TestA: hello",
+		"finished /TestA 0 false",
+		"cleanup /TestA",
+		"started /TestB 1",
+		"started /TestB/sub 1",
+		"failure /TestB/sub 1: sub fails",
+		"finished /TestB/sub 1 false",
+		"finished /TestB 1 false",
+		"started /TestB 2",
+		"finished /TestB 0 false",
+		"started /TestC 1",
+		"skip /TestC: later",
+		"finished /TestC 0 true",
+		"suite finished 0",
+	}, got)
+}
+
+func (EventsSuite) TestA(t *EventsT) {
+	t.Cleanup(func() {})
+	t.Log("hello")
+}
+
+func (EventsSuite) TestB(t *EventsT) {
+	if Inspect(t).Attempt == 1 {
+		Run(t, "sub", func(t *EventsT) { t.Error("sub fails") })
+	}
+}
+
+func (EventsSuite) TestC(t *EventsT) {
+	t.Skip("later")
+}
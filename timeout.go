@@ -0,0 +1,215 @@
+package testo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/metafates/testo/internal/reflectutil"
+	"github.com/metafates/testo/plugin"
+)
+
+// ErrTimeout is the cause of the [T.Context] cancellation when the test times out.
+var ErrTimeout = errors.New("test timed out")
+
+// timeoutGracePeriod is how long to wait for the test to return
+// after its context was cancelled due to timeout.
+const timeoutGracePeriod = time.Second
+
+// WithTimeout sets timeout for each suite test when passed to [RunSuite]
+// or for the subtest when passed to [Run].
+//
+// Timeout includes BeforeEach and AfterEach hooks.
+// Time spent waiting for other tests when calling Parallel is not counted.
+//
+// When the test times out, its [T.Context] is cancelled
+// with [ErrTimeout] cause and the test is failed.
+// If the test does not return shortly after that, it is abandoned:
+// testo stops waiting for it and ignores its further calls to T.
+//
+// Timeouts of individual suite tests can be set by the suite "Timeouts" method:
+//
+//	func (Suite) Timeouts() map[string]time.Duration {
+//		return map[string]time.Duration{"TestFoo": time.Second}
+//	}
+func WithTimeout(timeout time.Duration) plugin.Option {
+	return plugin.Option{Value: timeoutOption(timeout)}
+}
+
+type timeoutOption time.Duration
+
+// timeoutOf returns timeout from the given options.
+func timeoutOf(options []plugin.Option) time.Duration {
+	var timeout time.Duration
+
+	for _, o := range options {
+		if o, ok := o.Value.(timeoutOption); ok {
+			timeout = time.Duration(o)
+		}
+	}
+
+	return timeout
+}
+
+// suiteTimeoutsOf returns test timeouts declared by the "Timeouts" suite method.
+func suiteTimeoutsOf[Suite any, T fataller](t T) map[string]time.Duration {
+	const name = "Timeouts"
+
+	suite := reflect.TypeFor[Suite]()
+
+	method, ok := suite.MethodByName(name)
+	if !ok {
+		return nil
+	}
+
+	f, ok := method.Func.Interface().(func(Suite) map[string]time.Duration)
+	if !ok {
+		t.Fatalf(
+			"wrong signature for %[1]s.%[2]s, must be: func (%[1]s) %[2]s() map[string]time.Duration",
+			suite, name,
+		)
+
+		return nil
+	}
+
+	timeouts := f(reflectutil.Make[Suite]())
+
+	var unknown []string
+
+	for name := range timeouts {
+		m, ok := suite.MethodByName(name)
+
+		if !ok || !isTestMethod(m.Name) {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+
+		t.Fatalf(
+			"wrong timeouts for %[1]s: tests %[2]s not found",
+			suite, strings.Join(unknown, ", "),
+		)
+	}
+
+	return timeouts
+}
+
+// runWithTimeout runs f in a separate goroutine and waits
+// for it to finish or for the timeout to expire.
+//
+// Given t must be the one f runs with.
+func runWithTimeout(t *T, timeout time.Duration, f func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancelCause(t.parentContext())
+	defer cancel(context.Canceled)
+
+	t.setContext(ctx)
+
+	expired := make(chan struct{})
+
+	t.timer = time.AfterFunc(timeout, func() { close(expired) })
+	t.deadline = time.Now().Add(timeout)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		f()
+	}()
+
+	select {
+	case <-done:
+		t.timer.Stop()
+
+		return
+
+	case <-expired:
+	}
+
+	stage := t.describeStage()
+
+	cancel(fmt.Errorf("%w after %s", ErrTimeout, timeout))
+
+	select {
+	case <-done:
+	case <-time.After(timeoutGracePeriod):
+		t.abandoned.Store(true)
+
+		t.T.Logf("test did not return in %s after timeout, abandoning it", timeoutGracePeriod)
+	}
+
+	t.info.FailureKind = plugin.TestFailureKindTimeout
+
+	msg := fmt.Sprintf("test timed out after %s while running %s", timeout, stage)
+
+	t.addFailure(msg)
+
+	if t.captureFailures {
+		t.T.Log(msg)
+
+		return
+	}
+
+	t.T.Error(msg)
+}
+
+// pauseTimeout stops the timeout timer, if any, and returns remaining time.
+//
+// It reports false if there is no timeout or it has already expired.
+func (t *T) pauseTimeout() (time.Duration, bool) {
+	if t.timer == nil || !t.timer.Stop() {
+		return 0, false
+	}
+
+	return time.Until(t.deadline), true
+}
+
+// resumeTimeout restarts the timeout timer paused by [T.pauseTimeout].
+func (t *T) resumeTimeout(remaining time.Duration) {
+	t.deadline = time.Now().Add(remaining)
+	t.timer.Reset(remaining)
+}
+
+// isAbandoned states whether this T or any of its parents is abandoned.
+func (t *T) isAbandoned() bool {
+	for t != nil {
+		if t.abandoned.Load() {
+			return true
+		}
+
+		t = t.parent
+	}
+
+	return false
+}
+
+// describeStage describes what this T and its running subtests are currently doing.
+func (t *T) describeStage() string {
+	var stages []string
+
+	for t != nil {
+		if stage := t.stage.Load(); stage != nil {
+			stages = append(stages, *stage)
+		}
+
+		t = t.active.Load()
+	}
+
+	if len(stages) == 0 {
+		return "test"
+	}
+
+	return strings.Join(stages, " > ")
+}
+
+func (t *T) setStage(stage string) {
+	t.stage.Store(&stage)
+}